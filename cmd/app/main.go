@@ -1,6 +1,8 @@
 package main
 
 import (
+	_ "time/tzdata"
+
 	"github.com/zenorachi/todo-service/internal/app"
 	"github.com/zenorachi/todo-service/internal/config"
 )
@@ -12,10 +12,13 @@ import (
 	"github.com/zenorachi/todo-service/internal/server"
 	"github.com/zenorachi/todo-service/internal/service"
 	"github.com/zenorachi/todo-service/internal/transport"
+	"github.com/zenorachi/todo-service/internal/worker"
 	"github.com/zenorachi/todo-service/pkg/auth"
 	"github.com/zenorachi/todo-service/pkg/database/postgres"
 	"github.com/zenorachi/todo-service/pkg/hash"
 	"github.com/zenorachi/todo-service/pkg/logger"
+	"github.com/zenorachi/todo-service/pkg/notify"
+	"github.com/zenorachi/todo-service/pkg/storage"
 )
 
 // @title           			TO-DO service
@@ -58,6 +61,9 @@ func Run(cfg *config.Config) {
 		TokenManager:    tokenManager,
 		AccessTokenTTL:  cfg.Auth.AccessTokenTTL,
 		RefreshTokenTTL: cfg.Auth.RefreshTokenTTL,
+		BlobStore:       newBlobStore(&cfg.Attachments),
+		MaxFileSize:     cfg.Attachments.MaxFileSize,
+		MaxUserSize:     cfg.Attachments.MaxUserSize,
 	})
 
 	/* INIT HTTP HANDLER */
@@ -68,6 +74,21 @@ func Run(cfg *config.Config) {
 	srv.Run()
 	logger.Info("server", "http server started")
 
+	/* INIT & RUN REMINDERS DISPATCHER */
+	dispatcher := worker.NewRemindersDispatcher(cfg, services.Reminders, newNotifier(&cfg.Reminders))
+	dispatcher.Run()
+	logger.Info("reminders", "reminders dispatcher started")
+
+	/* INIT & RUN TRASH PURGER */
+	purger := worker.NewTrashPurger(cfg, services.Agenda, services.Attachments)
+	purger.Run()
+	logger.Info("trash", "trash purger started")
+
+	/* INIT & RUN TASK ARCHIVER */
+	archiver := worker.NewTaskArchiver(cfg, services.Agenda)
+	archiver.Run()
+	logger.Info("archive", "task archiver started")
+
 	/* GRACEFUL SHUTDOWN */
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
@@ -82,7 +103,39 @@ func Run(cfg *config.Config) {
 
 	/* SHUTTING DOWN */
 	logger.Info("Shutting down...")
-	if srv.Shutdown() != nil {
+	if err = srv.Shutdown(); err != nil {
 		logger.Error("server", err.Error())
 	}
+	if err = dispatcher.Shutdown(); err != nil {
+		logger.Error("reminders", err.Error())
+	}
+	if err = purger.Shutdown(); err != nil {
+		logger.Error("trash", err.Error())
+	}
+	if err = archiver.Shutdown(); err != nil {
+		logger.Error("archive", err.Error())
+	}
+}
+
+func newNotifier(cfg *config.RemindersConfig) notify.Notifier {
+	switch cfg.Notifier {
+	case "webhook":
+		return notify.NewWebhookNotifier(cfg.Webhook.URL, cfg.Webhook.Timeout)
+	case "smtp":
+		return notify.NewSMTPNotifier(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.From, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Timeout)
+	default:
+		return notify.NewLogNotifier()
+	}
+}
+
+func newBlobStore(cfg *config.AttachmentsConfig) storage.BlobStore {
+	if cfg.Store != "s3" {
+		return storage.NewLocalStore(cfg.Local.Dir)
+	}
+
+	store, err := storage.NewS3Store(cfg.S3.Endpoint, cfg.S3.Bucket, cfg.S3.Region, cfg.S3.AccessKey, cfg.S3.SecretKey)
+	if err != nil {
+		logger.Fatal("attachments", err.Error())
+	}
+	return store
 }
@@ -13,10 +13,14 @@ import (
 )
 
 type Config struct {
-	HTTP HTTPConfig
-	Auth AuthConfig
-	GIN  GINConfig
-	DB   postgres.DBConfig
+	HTTP        HTTPConfig
+	Auth        AuthConfig
+	GIN         GINConfig
+	DB          postgres.DBConfig
+	Reminders   RemindersConfig
+	Trash       TrashConfig
+	Archive     ArchiveConfig
+	Attachments AttachmentsConfig
 }
 
 type (
@@ -38,6 +42,66 @@ type (
 	GINConfig struct {
 		Mode string
 	}
+
+	RemindersConfig struct {
+		PollInterval    time.Duration
+		BatchSize       int
+		ShutdownTimeout time.Duration
+		MaxAttempts     int
+		RetryBackoff    time.Duration
+		MaxRetryBackoff time.Duration
+		NotifyTimeout   time.Duration
+		Notifier        string
+		Webhook         WebhookConfig
+		SMTP            SMTPConfig
+	}
+
+	WebhookConfig struct {
+		URL     string
+		Timeout time.Duration
+	}
+
+	SMTPConfig struct {
+		Host     string
+		Port     string
+		From     string
+		Username string
+		Password string
+		Timeout  time.Duration
+	}
+
+	TrashConfig struct {
+		Retention       time.Duration
+		PurgeInterval   time.Duration
+		ShutdownTimeout time.Duration
+	}
+
+	ArchiveConfig struct {
+		After           time.Duration
+		Interval        time.Duration
+		BatchSize       int
+		ShutdownTimeout time.Duration
+	}
+
+	AttachmentsConfig struct {
+		Store       string
+		MaxFileSize int64
+		MaxUserSize int64
+		Local       LocalStoreConfig
+		S3          S3Config
+	}
+
+	LocalStoreConfig struct {
+		Dir string
+	}
+
+	S3Config struct {
+		Endpoint  string
+		Bucket    string
+		Region    string
+		AccessKey string
+		SecretKey string
+	}
 )
 
 var (
@@ -80,6 +144,14 @@ func New() *Config {
 		if err := envconfig.Process("gin", &config.GIN); err != nil {
 			logger.Fatal("gin config", err.Error())
 		}
+
+		if err := envconfig.Process("smtp", &config.Reminders.SMTP); err != nil {
+			logger.Fatal("smtp config", err.Error())
+		}
+
+		if err := envconfig.Process("s3", &config.Attachments.S3); err != nil {
+			logger.Fatal("s3 config", err.Error())
+		}
 	})
 
 	return config
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Attachment is the metadata of a file uploaded to the task, the content itself lives in the blob store under StorageKey.
+type Attachment struct {
+	ID          int       `json:"id,omitempty"`
+	TaskID      int       `json:"task_id,omitempty"`
+	UserID      int       `json:"user_id,omitempty"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+// ChecklistItem is a lightweight step inside a task, the items of a task are ordered by Position starting from 0.
+type ChecklistItem struct {
+	ID       int    `json:"id,omitempty"`
+	TaskID   int    `json:"task_id,omitempty"`
+	Text     string `json:"text"`
+	Checked  bool   `json:"checked"`
+	Position int    `json:"position"`
+}
+
+type ChecklistItemUpdate struct {
+	Text     *string
+	Checked  *bool
+	Position *int
+}
+
+func (u ChecklistItemUpdate) IsEmpty() bool {
+	return u.Text == nil && u.Checked == nil && u.Position == nil
+}
+
+// ChecklistProgress returns the percentage of the checked items, nil for an empty checklist.
+func ChecklistProgress(items []ChecklistItem) *int {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var checked int
+	for _, item := range items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	progress := checked * 100 / len(items)
+	return &progress
+}
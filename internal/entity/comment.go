@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Comment is a note left on the task by its author, UpdatedAt is set once the comment is edited.
+type Comment struct {
+	ID        int        `json:"id,omitempty"`
+	TaskID    int        `json:"task_id,omitempty"`
+	UserID    int        `json:"user_id,omitempty"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
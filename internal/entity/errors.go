@@ -3,16 +3,59 @@ package entity
 import "errors"
 
 var (
-	ErrInvalidInput           = errors.New("invalid input")
-	ErrEmptyAuthHeader        = errors.New("empty authorization header")
-	ErrInvalidAuthHeader      = errors.New("invalid authorization header")
-	ErrUserAlreadyExists      = errors.New("user with such login/email already exists")
-	ErrUserDoesNotExist       = errors.New("user does not exist")
-	ErrIncorrectPassword      = errors.New("incorrect password")
-	ErrSessionDoesNotExist    = errors.New("session does not exist")
-	ErrTaskAlreadyExist       = errors.New("task already exist")
-	ErrTaskDoesNotExist       = errors.New("task does not exist")
-	ErrInvalidStatus          = errors.New("invalid status (status should be 'done' or 'not done'")
-	ErrInvalidData            = errors.New("invalid data (should be like `2006-Jan-02`")
-	ErrInvalidPaginationSizes = errors.New("invalid pagination sizes")
+	ErrInvalidInput              = errors.New("invalid input")
+	ErrEmptyAuthHeader           = errors.New("empty authorization header")
+	ErrInvalidAuthHeader         = errors.New("invalid authorization header")
+	ErrUserAlreadyExists         = errors.New("user with such login/email already exists")
+	ErrUserDoesNotExist          = errors.New("user does not exist")
+	ErrIncorrectPassword         = errors.New("incorrect password")
+	ErrSessionDoesNotExist       = errors.New("session does not exist")
+	ErrTaskAlreadyExist          = errors.New("task already exist")
+	ErrTaskDoesNotExist          = errors.New("task does not exist")
+	ErrInvalidStatus             = errors.New("invalid status (status should be one of the workflow statuses)")
+	ErrStatusNotAllowed          = errors.New("status transition is not allowed by the workflow")
+	ErrStatusAlreadyExists       = errors.New("status already exists")
+	ErrStatusDoesNotExist        = errors.New("status does not exist")
+	ErrStatusInUse               = errors.New("status is used by tasks")
+	ErrInvalidPriority           = errors.New("invalid priority (priority should be 'none', 'low', 'medium', 'high' or 'urgent')")
+	ErrInvalidData               = errors.New("invalid date (should be RFC 3339 like `2006-01-02T15:04:05+07:00` or date only like `2006-01-02`)")
+	ErrInvalidPaginationSizes    = errors.New("invalid pagination sizes")
+	ErrEmptyTaskUpdate           = errors.New("nothing to update")
+	ErrTagAlreadyExists          = errors.New("tag already exists")
+	ErrTagDoesNotExist           = errors.New("tag does not exist")
+	ErrProjectAlreadyExists      = errors.New("project already exists")
+	ErrProjectDoesNotExist       = errors.New("project does not exist")
+	ErrParentTaskDoesNotExist    = errors.New("parent task does not exist")
+	ErrTaskIsNotArchived         = errors.New("task is not archived")
+	ErrTaskIsNotInTrash          = errors.New("task is not in the trash")
+	ErrTaskHasSubtasks           = errors.New("task has subtasks (use cascade to delete them too)")
+	ErrInvalidTimezone           = errors.New("invalid timezone (should be an IANA time zone like `Asia/Tokyo`)")
+	ErrInvalidReminder           = errors.New("invalid reminder (exactly one of remind_at and non-negative offset_minutes should be set)")
+	ErrReminderDoesNotExist      = errors.New("reminder does not exist")
+	ErrBlockerDoesNotExist       = errors.New("blocker task does not exist")
+	ErrDependencyAlreadyExists   = errors.New("dependency already exists")
+	ErrDependencyDoesNotExist    = errors.New("dependency does not exist")
+	ErrDependencyCycle           = errors.New("dependency would create a cycle")
+	ErrTaskIsBlocked             = errors.New("task is blocked by not completed tasks (use force to complete it anyway)")
+	ErrCommentDoesNotExist       = errors.New("comment does not exist")
+	ErrNotCommentAuthor          = errors.New("only the author can edit or delete the comment")
+	ErrAttachmentDoesNotExist    = errors.New("attachment does not exist")
+	ErrAttachmentTooLarge        = errors.New("attachment is larger than the allowed file size")
+	ErrStorageQuotaExceeded      = errors.New("attachments exceed the storage quota of the user")
+	ErrChecklistItemDoesNotExist = errors.New("checklist item does not exist")
+	ErrTimeEntryDoesNotExist     = errors.New("time entry does not exist")
+	ErrInvalidTimeEntry          = errors.New("invalid time entry (started_at should not be after stopped_at, both not in the future)")
+	ErrTimerAlreadyRunning       = errors.New("another timer is already running (stop it first)")
+	ErrTimerIsNotRunning         = errors.New("no timer is running")
+	ErrInvalidDateRange          = errors.New("invalid date range (from should not be after to, at most a year)")
+	ErrInvalidMove               = errors.New("invalid move (exactly one of before_id and after_id other than the task itself should be set)")
+	ErrTemplateAlreadyExists     = errors.New("template already exists")
+	ErrTemplateDoesNotExist      = errors.New("template does not exist")
+	ErrMissingTemplateVariables  = errors.New("values of some template variables are not provided")
+	ErrInvalidFilterQuery        = errors.New("invalid filter query")
+	ErrInvalidView               = errors.New("invalid view (should be overdue, upcoming or no_date, upcoming days from 1 to 366)")
+	ErrInvalidCursor             = errors.New("invalid cursor (should be the next_cursor of the previous page)")
+	ErrInvalidSort               = errors.New("invalid sort (should be comma-separated fields like -priority,date,title)")
+	ErrInvalidSearchQuery        = errors.New("invalid search query (should contain at least one letter or digit)")
+	ErrInvalidRecurrence         = errors.New("invalid recurrence (should be an iCalendar RRULE like `FREQ=WEEKLY;BYDAY=MO`)")
 )
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+const (
+	HistoryCreated       = "created"
+	HistoryUpdated       = "updated"
+	HistoryStatusChanged = "status_changed"
+	HistoryMoved         = "moved"
+	HistoryReordered     = "reordered"
+	HistoryDeleted       = "deleted"
+	HistoryRestored      = "restored"
+	HistoryArchived      = "archived"
+	HistoryUnarchived    = "unarchived"
+)
+
+// HistoryEntry is a recorded change of the task, Changes maps the changed fields to their old and new values.
+type HistoryEntry struct {
+	ID        int                    `json:"id,omitempty"`
+	TaskID    int                    `json:"task_id,omitempty"`
+	UserID    int                    `json:"user_id,omitempty"`
+	Action    string                 `json:"action"`
+	Changes   map[string]FieldChange `json:"changes,omitempty"`
+	ChangedAt time.Time              `json:"changed_at"`
+}
+
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+type Project struct {
+	ID          int    `json:"id,omitempty"`
+	UserID      int    `json:"user_id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Reminder fires either at the absolute RemindAt time or OffsetMinutes before the task date,
+// exactly one of them is set. FireAt is the resulting time of the reminder, it is nil for a relative
+// reminder of a task without date (such a reminder waits until the task gets a date).
+type Reminder struct {
+	ID            int        `json:"id,omitempty"`
+	UserID        int        `json:"user_id,omitempty"`
+	TaskID        int        `json:"task_id,omitempty"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty"`
+	FireAt        *time.Time `json:"fire_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// DueReminder is a claimed reminder with the details needed to deliver it.
+type DueReminder struct {
+	ReminderID int
+	UserID     int
+	Email      string
+	TaskID     int
+	Title      string
+	Date       *time.Time
+	FireAt     time.Time
+}
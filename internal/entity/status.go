@@ -0,0 +1,22 @@
+package entity
+
+// Status is a step of the user's workflow, statuses are configured per user or per project
+// (ProjectID is set). Completed marks the statuses counted as finished work, Transitions
+// lists the statuses a task can be moved to from this one (empty means any).
+type Status struct {
+	ID          int      `json:"id,omitempty"`
+	UserID      int      `json:"user_id,omitempty"`
+	ProjectID   *int     `json:"project_id,omitempty"`
+	Name        string   `json:"name"`
+	Completed   bool     `json:"completed"`
+	Position    int      `json:"position"`
+	Transitions []string `json:"transitions,omitempty"`
+}
+
+// DefaultStatuses are the statuses every workflow has besides the configured ones.
+func DefaultStatuses() []Status {
+	return []Status{
+		{Name: StatusNotDone, Position: 0},
+		{Name: StatusDone, Completed: true, Position: 1},
+	}
+}
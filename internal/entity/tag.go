@@ -0,0 +1,7 @@
+package entity
+
+type Tag struct {
+	ID     int    `json:"id,omitempty"`
+	UserID int    `json:"user_id,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
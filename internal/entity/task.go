@@ -1,17 +1,143 @@
 package entity
 
-import "time"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
 
 const (
 	StatusDone    = "done"
 	StatusNotDone = "not done"
+
+	// StatusNotDoneAlias is the spelling of StatusNotDone accepted from the v1 clients.
+	StatusNotDoneAlias = "not_done"
+)
+
+const (
+	PriorityNone   = "none"
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
 )
 
 type Task struct {
-	ID          int       `json:"id,omitempty"`
-	UserID      int       `json:"user_id,omitempty"`
-	Title       string    `json:"title,omitempty"`
-	Description string    `json:"description,omitempty"`
-	Date        time.Time `json:"date,omitempty"`
-	Status      string    `json:"status,omitempty"`
+	ID              int             `json:"id,omitempty"`
+	UserID          int             `json:"user_id,omitempty"`
+	Title           string          `json:"title,omitempty"`
+	Description     string          `json:"description,omitempty"`
+	Date            *time.Time      `json:"date,omitempty"` // nil for a task without date
+	Status          string          `json:"status,omitempty"`
+	Priority        string          `json:"priority,omitempty"`
+	ProjectID       *int            `json:"project_id,omitempty"`
+	ParentID        *int            `json:"parent_id,omitempty"`
+	Recurrence      string          `json:"recurrence,omitempty"`
+	CompletedAt     *time.Time      `json:"completed_at,omitempty"`
+	ArchivedAt      *time.Time      `json:"archived_at,omitempty"`
+	EstimateMinutes int             `json:"estimate_minutes,omitempty"` // zero means not estimated
+	EstimatePoints  int             `json:"estimate_points,omitempty"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty"`
+	Tags            []string        `json:"tags,omitempty"`
+	Subtasks        []Task          `json:"subtasks,omitempty"`
+	Checklist       []ChecklistItem `json:"checklist,omitempty"`
+	Progress        *int            `json:"progress,omitempty"`     // percentage of the checked checklist items
+	TrackedTime     int64           `json:"tracked_time,omitempty"` // seconds tracked on the task, including a running timer
+}
+
+type TaskUpdate struct {
+	Title           *string
+	Description     *string
+	Date            *time.Time // a zero date removes the date
+	Status          *string
+	Priority        *string
+	Recurrence      *string
+	Tags            *[]string
+	EstimateMinutes *int
+	EstimatePoints  *int
+	Completed       *bool // set from the workflow of the new status
+}
+
+func (t TaskUpdate) IsEmpty() bool {
+	return t.Title == nil && t.Description == nil && t.Date == nil && t.Status == nil && t.Priority == nil &&
+		t.Recurrence == nil && t.Tags == nil && t.EstimateMinutes == nil && t.EstimatePoints == nil
+}
+
+// Views are the predefined date windows of the task listing.
+const (
+	ViewOverdue  = "overdue"  // not completed tasks due before now
+	ViewUpcoming = "upcoming" // tasks due from now till the end of the Days-th day (today is the first one)
+	ViewNoDate   = "no_date"  // tasks without date
+)
+
+type TaskFilter struct {
+	Status       string
+	Completed    *bool
+	From         time.Time // start of the date range in the user's timezone, zero means unbounded
+	To           time.Time // end of the date range (exclusive), zero means unbounded
+	NoDate       bool      // only tasks without date
+	View         string    // one of the views, resolved into the date range by the service
+	Days         int       // length of the upcoming view in days
+	Priority     string
+	ProjectID    *int
+	Tags         []string
+	TagsMatchAll bool
+	Actionable   bool           // only tasks whose blockers are all completed
+	Query        string         // filter query language, see pkg/filter
+	Location     *time.Location // location of the user, the dates of the query are the days in it (UTC if nil)
+	AsTree       bool
+	Sort         string      // comma-separated fields, a leading "-" sorts descending, default - rank
+	After        *TaskCursor // keyset pagination, the page starts right after the task
+	Limit        int
+	Offset       int
+}
+
+// TaskPage is a page of the task listing, NextCursor is nil on the last page.
+type TaskPage struct {
+	Tasks      []Task
+	Total      int // number of the tasks matching the filter on all pages
+	NextCursor *TaskCursor
+}
+
+// TaskCursor is the position of a task in the listing order, clients get it as an opaque string.
+type TaskCursor struct {
+	Sort   string    `json:"s,omitempty"` // sort of the listing, the cursor is valid only with it
+	Values []*string `json:"v"`           // values of the sort fields of the task, nil for NULL
+	ID     int       `json:"i"`
+}
+
+func (c TaskCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeTaskCursor(value string) (TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+
+	var cursor TaskCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID < 1 {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// TaskSearchResult is a task matching the search query, the matched words of the snippets are wrapped in <mark> tags.
+type TaskSearchResult struct {
+	Task               Task    `json:"task"`
+	Score              float64 `json:"score"`
+	TitleSnippet       string  `json:"title_snippet"`
+	DescriptionSnippet string  `json:"description_snippet,omitempty"`
+}
+
+func IsValidPriority(priority string) bool {
+	switch priority {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	default:
+		return false
+	}
 }
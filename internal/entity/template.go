@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"regexp"
+	"sort"
+)
+
+// templateVariable matches the `{{name}}` placeholders of the template text.
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// Template is a named task blueprint, the task is due DueOffsetMinutes after its instantiation
+// (zero means the task has no date) and gets the checklist items in the given order.
+type Template struct {
+	ID               int      `json:"id,omitempty"`
+	UserID           int      `json:"user_id,omitempty"`
+	Name             string   `json:"name"`
+	Title            string   `json:"title"`
+	Description      string   `json:"description,omitempty"`
+	DueOffsetMinutes int      `json:"due_offset_minutes"`
+	Tags             []string `json:"tags"`
+	Checklist        []string `json:"checklist"`
+}
+
+// Variables returns the sorted names of the variables used in the title and the description.
+func (t Template) Variables() []string {
+	seen := make(map[string]bool)
+	for _, text := range []string{t.Title, t.Description} {
+		for _, match := range templateVariable.FindAllStringSubmatch(text, -1) {
+			seen[match[1]] = true
+		}
+	}
+
+	variables := make([]string, 0, len(seen))
+	for name := range seen {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+
+	return variables
+}
+
+// RenderTemplate substitutes the variables of the text, the placeholders of missing variables are kept as is.
+func RenderTemplate(text string, variables map[string]string) string {
+	return templateVariable.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := variables[templateVariable.FindStringSubmatch(placeholder)[1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,86 @@
+package entity
+
+import "time"
+
+// TimeEntry is time tracked on the task, a running timer has no StoppedAt yet.
+// Duration is in seconds, for a running timer it is counted up to now.
+type TimeEntry struct {
+	ID        int        `json:"id,omitempty"`
+	TaskID    int        `json:"task_id,omitempty"`
+	UserID    int        `json:"user_id,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	Duration  int64      `json:"duration"`
+}
+
+type TimeEntryUpdate struct {
+	StartedAt *time.Time
+	StoppedAt *time.Time
+	Note      *string
+}
+
+func (u TimeEntryUpdate) IsEmpty() bool {
+	return u.StartedAt == nil && u.StoppedAt == nil && u.Note == nil
+}
+
+// TimeReportRow is the time tracked on the task during the calendar day (in the user's timezone), in seconds.
+type TimeReportRow struct {
+	Date      string
+	TaskID    int
+	TaskTitle string
+	Duration  int64
+}
+
+type TimeReport struct {
+	From  string           `json:"from"`
+	To    string           `json:"to"`
+	Total int64            `json:"total"`
+	Days  []TimeReportDay  `json:"days"`
+	Tasks []TimeReportTask `json:"tasks"`
+}
+
+type TimeReportDay struct {
+	Date     string           `json:"date"`
+	Duration int64            `json:"duration"`
+	Tasks    []TimeReportTask `json:"tasks"`
+}
+
+type TimeReportTask struct {
+	TaskID   int    `json:"task_id"`
+	Title    string `json:"title"`
+	Duration int64  `json:"duration"`
+}
+
+// NewTimeReport sums the rows (ordered by date) up per day and per task.
+func NewTimeReport(from, to string, rows []TimeReportRow) TimeReport {
+	report := TimeReport{
+		From:  from,
+		To:    to,
+		Days:  []TimeReportDay{},
+		Tasks: []TimeReportTask{},
+	}
+
+	positions := make(map[int]int)
+	for _, row := range rows {
+		task := TimeReportTask{TaskID: row.TaskID, Title: row.TaskTitle, Duration: row.Duration}
+
+		if last := len(report.Days) - 1; last < 0 || report.Days[last].Date != row.Date {
+			report.Days = append(report.Days, TimeReportDay{Date: row.Date})
+		}
+		day := &report.Days[len(report.Days)-1]
+		day.Duration += row.Duration
+		day.Tasks = append(day.Tasks, task)
+
+		if position, ok := positions[row.TaskID]; ok {
+			report.Tasks[position].Duration += row.Duration
+		} else {
+			positions[row.TaskID] = len(report.Tasks)
+			report.Tasks = append(report.Tasks, task)
+		}
+
+		report.Total += row.Duration
+	}
+
+	return report
+}
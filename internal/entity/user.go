@@ -7,5 +7,6 @@ type User struct {
 	Login        string    `json:"login,omitempty"`
 	Email        string    `json:"email,omitempty"`
 	Password     string    `json:"password,omitempty"`
+	Timezone     string    `json:"timezone,omitempty"`
 	RegisteredAt time.Time `json:"registered_at,omitempty"`
 }
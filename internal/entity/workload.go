@@ -0,0 +1,53 @@
+package entity
+
+// Workload is the number of tasks and the sum of their estimates.
+type Workload struct {
+	Tasks   int `json:"tasks"`
+	Minutes int `json:"minutes"`
+	Points  int `json:"points"`
+}
+
+// WorkloadDay compares the tasks planned for the calendar day (in the user's timezone) with the tasks completed during it,
+// Overdue is the load of the tasks planned before the day and not completed by its start.
+type WorkloadDay struct {
+	Date      string   `json:"date"`
+	Planned   Workload `json:"planned"`
+	Completed Workload `json:"completed"`
+	Overdue   Workload `json:"overdue"`
+}
+
+type WorkloadReport struct {
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	Planned   Workload      `json:"planned"`
+	Completed Workload      `json:"completed"`
+	Days      []WorkloadDay `json:"days"`
+}
+
+// NewWorkloadReport sums the planned and the completed workload of the days up.
+func NewWorkloadReport(from, to string, days []WorkloadDay) WorkloadReport {
+	report := WorkloadReport{
+		From: from,
+		To:   to,
+		Days: days,
+	}
+
+	if report.Days == nil {
+		report.Days = []WorkloadDay{}
+	}
+
+	for _, day := range days {
+		report.Planned = report.Planned.add(day.Planned)
+		report.Completed = report.Completed.add(day.Completed)
+	}
+
+	return report
+}
+
+func (w Workload) add(other Workload) Workload {
+	return Workload{
+		Tasks:   w.Tasks + other.Tasks,
+		Minutes: w.Minutes + other.Minutes,
+		Points:  w.Points + other.Points,
+	}
+}
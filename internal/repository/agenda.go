@@ -4,11 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/pkg/rank"
 )
 
+var taskColumns = fmt.Sprintf("id, title, description, date, status, priority, project_id, parent_id, recurrence, completed_at, "+
+	"archived_at, estimate_minutes, estimate_points, "+
+	"ARRAY(SELECT %[2]s.name FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.tag_id WHERE %[1]s.task_id = %[3]s.id ORDER BY %[2]s.name), "+
+	"(SELECT COALESCE(SUM(%[5]s), 0) FROM %[4]s WHERE %[4]s.task_id = %[3]s.id)",
+	collectionAgendaTags, collectionTags, collectionAgenda, collectionTimeEntries, timeEntryDuration)
+
 type AgendaRepository struct {
 	db *sql.DB
 }
@@ -27,13 +37,7 @@ func (a *AgendaRepository) Create(ctx context.Context, task entity.Task) (int, e
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	var (
-		id    int
-		query = fmt.Sprintf("INSERT INTO %s (user_id, title, description, date, status) VALUES ($1, $2, $3, $4, $5) RETURNING id",
-			collectionAgenda)
-	)
-
-	err = tx.QueryRowContext(ctx, query, task.UserID, task.Title, task.Description, task.Date, task.Status).Scan(&id)
+	id, err := createTask(ctx, tx, task)
 	if err != nil {
 		return 0, err
 	}
@@ -51,14 +55,7 @@ func (a *AgendaRepository) GetByID(ctx context.Context, id int, userId int) (ent
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	var (
-		task  entity.Task
-		query = fmt.Sprintf("SELECT title, description, date, status FROM %s WHERE id = $1 AND user_id = $2",
-			collectionAgenda)
-	)
-
-	err = tx.QueryRowContext(ctx, query, id, userId).
-		Scan(&task.Title, &task.Description, &task.Date, &task.Status)
+	task, err := getTaskByID(ctx, tx, id, userId)
 	if err != nil {
 		return entity.Task{}, err
 	}
@@ -66,7 +63,7 @@ func (a *AgendaRepository) GetByID(ctx context.Context, id int, userId int) (ent
 	return task, tx.Commit()
 }
 
-func (a *AgendaRepository) GetByTitleAndUserID(ctx context.Context, title string, userId int) (entity.Task, error) {
+func (a *AgendaRepository) GetByTitleAndUserID(ctx context.Context, title string, userId int, projectId *int) (entity.Task, error) {
 	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelRepeatableRead,
 		ReadOnly:  true,
@@ -76,14 +73,10 @@ func (a *AgendaRepository) GetByTitleAndUserID(ctx context.Context, title string
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	var (
-		task  entity.Task
-		query = fmt.Sprintf("SELECT title, description, date, status FROM %s WHERE title = $1 AND user_id = $2",
-			collectionAgenda)
-	)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE title = $1 AND user_id = $2 AND project_id IS NOT DISTINCT FROM $3 AND deleted_at IS NULL",
+		taskColumns, collectionAgenda)
 
-	err = tx.QueryRowContext(ctx, query, title, userId).
-		Scan(&task.Title, &task.Description, &task.Date, &task.Status)
+	task, err := scanTask(tx.QueryRowContext(ctx, query, title, userId, projectId))
 	if err != nil {
 		return entity.Task{}, err
 	}
@@ -91,7 +84,9 @@ func (a *AgendaRepository) GetByTitleAndUserID(ctx context.Context, title string
 	return task, tx.Commit()
 }
 
-func (a *AgendaRepository) SetStatus(ctx context.Context, id int, userId int, status string) error {
+// SetStatus sets the task status, completed_at is kept for already completed tasks and is reset for not completed ones.
+// The next occurrence of the completed recurring task is created in the same transaction, see createNextOccurrence.
+func (a *AgendaRepository) SetStatus(ctx context.Context, id int, userId int, status string, completed, withSubtasks bool, next *entity.Task) error {
 	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
 		ReadOnly:  false,
@@ -101,10 +96,156 @@ func (a *AgendaRepository) SetStatus(ctx context.Context, id int, userId int, st
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query := fmt.Sprintf("UPDATE %s SET status = $1 WHERE id = $2 AND user_id = $3",
-		collectionAgenda)
+	var (
+		tasks = fmt.Sprintf("SELECT id, status FROM %s WHERE id = $3 AND user_id = $4", collectionAgenda)
+		value = "$1"
+		cte   string
+		args  = []any{status, completed, id, userId}
+	)
+	if withSubtasks {
+		// the subtasks not completed yet are completed with the completed status of their own workflow
+		tasks = fmt.Sprintf("SELECT id, status FROM %s WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL "+
+			"AND (id = $3 OR completed_at IS NULL)", collectionAgenda)
+		value = fmt.Sprintf("CASE WHEN %s.id = $3 THEN $1 ELSE %s END", collectionAgenda, completedStatusQuery(4, 5))
+		cte = subtreeQuery(3, 4) + " "
+		args = append(args, entity.StatusDone)
+	}
+
+	query := fmt.Sprintf("%[1]sUPDATE %[2]s SET status = %[3]s, %[4]s FROM (%[5]s) AS old WHERE %[2]s.id = old.id "+
+		"RETURNING %[2]s.id, old.status, %[2]s.status",
+		cte, collectionAgenda, value, completedAtValue(2), tasks)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	var (
+		updated int
+		entries []entity.HistoryEntry
+	)
+	for rows.Next() {
+		var (
+			taskId               int
+			oldStatus, newStatus string
+		)
+
+		if err = rows.Scan(&taskId, &oldStatus, &newStatus); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		updated++
+
+		if oldStatus != newStatus {
+			entries = append(entries, entity.HistoryEntry{
+				TaskID:  taskId,
+				UserID:  userId,
+				Action:  entity.HistoryStatusChanged,
+				Changes: map[string]entity.FieldChange{"status": {Old: oldStatus, New: newStatus}},
+			})
+		}
+	}
+
+	if err = closeRows(rows); err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err = recordHistory(ctx, tx, entries...); err != nil {
+		return err
+	}
+
+	if next != nil {
+		if err = createNextOccurrence(ctx, tx, *next); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *AgendaRepository) SetProject(ctx context.Context, id, userId int, projectId *int) error {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		oldProjectId *int
+		query        = fmt.Sprintf("UPDATE %[1]s SET project_id = $1 FROM (SELECT id, project_id FROM %[1]s WHERE id = $2 AND user_id = $3) AS old "+
+			"WHERE %[1]s.id = old.id RETURNING old.project_id", collectionAgenda)
+	)
+
+	err = tx.QueryRowContext(ctx, query, projectId, id, userId).Scan(&oldProjectId)
+	if err != nil {
+		return err
+	}
+
+	err = recordHistory(ctx, tx, entity.HistoryEntry{
+		TaskID:  id,
+		UserID:  userId,
+		Action:  entity.HistoryMoved,
+		Changes: map[string]entity.FieldChange{"project_id": {Old: oldProjectId, New: projectId}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Move puts the task right before (or after) the target task in the user's manual order,
+// only the rank of the moved task changes (and is recorded in its history).
+func (a *AgendaRepository) Move(ctx context.Context, id, userId, targetId int, before bool) error {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = lockRanks(ctx, tx, userId); err != nil {
+		return err
+	}
+
+	position, err := movedRank(ctx, tx, userId, targetId, before)
+	if err != nil {
+		return err
+	}
+
+	if len(position) > rank.MaxLength {
+		if err = renumberRanks(ctx, tx, userId); err != nil {
+			return err
+		}
 
-	_, err = tx.ExecContext(ctx, query, status, id, userId)
+		if position, err = movedRank(ctx, tx, userId, targetId, before); err != nil {
+			return err
+		}
+	}
+
+	var oldPosition string
+	query := fmt.Sprintf("UPDATE %[1]s SET rank = $1 FROM (SELECT id, rank FROM %[1]s WHERE id = $2 AND user_id = $3) AS old "+
+		"WHERE %[1]s.id = old.id RETURNING old.rank", collectionAgenda)
+
+	if err = tx.QueryRowContext(ctx, query, position, id, userId).Scan(&oldPosition); err != nil {
+		return err
+	}
+
+	err = recordHistory(ctx, tx, entity.HistoryEntry{
+		TaskID:  id,
+		UserID:  userId,
+		Action:  entity.HistoryReordered,
+		Changes: map[string]entity.FieldChange{"rank": {Old: oldPosition, New: position}},
+	})
 	if err != nil {
 		return err
 	}
@@ -112,7 +253,136 @@ func (a *AgendaRepository) SetStatus(ctx context.Context, id int, userId int, st
 	return tx.Commit()
 }
 
-func (a *AgendaRepository) DeleteByID(ctx context.Context, id int, userId int) error {
+// Update applies the update to the task, the next occurrence of the recurring task completed by it is created
+// in the same transaction, see createNextOccurrence.
+func (a *AgendaRepository) Update(ctx context.Context, id, userId int, update entity.TaskUpdate, next *entity.Task) (entity.Task, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Task{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	old, err := getTaskByID(ctx, tx, id, userId)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	var (
+		values = make([]string, 0)
+		args   = make([]any, 0)
+		argId  = 1
+	)
+
+	if update.Title != nil {
+		values = append(values, fmt.Sprintf("title = $%d", argId))
+		args = append(args, *update.Title)
+		argId++
+	}
+
+	if update.Description != nil {
+		values = append(values, fmt.Sprintf("description = $%d", argId))
+		args = append(args, *update.Description)
+		argId++
+	}
+
+	if update.Date != nil {
+		values = append(values, fmt.Sprintf("date = $%d", argId))
+		if update.Date.IsZero() {
+			args = append(args, nil)
+		} else {
+			args = append(args, *update.Date)
+		}
+		argId++
+	}
+
+	if update.Status != nil {
+		values = append(values, fmt.Sprintf("status = $%d", argId))
+		args = append(args, *update.Status)
+		argId++
+	}
+
+	if update.Completed != nil {
+		values = append(values, completedAtValue(argId))
+		args = append(args, *update.Completed)
+		argId++
+	}
+
+	if update.Priority != nil {
+		values = append(values, fmt.Sprintf("priority = $%d", argId))
+		args = append(args, *update.Priority)
+		argId++
+	}
+
+	if update.Recurrence != nil {
+		values = append(values, fmt.Sprintf("recurrence = $%d", argId))
+		args = append(args, *update.Recurrence)
+		argId++
+	}
+
+	if update.EstimateMinutes != nil {
+		values = append(values, fmt.Sprintf("estimate_minutes = $%d", argId))
+		args = append(args, *update.EstimateMinutes)
+		argId++
+	}
+
+	if update.EstimatePoints != nil {
+		values = append(values, fmt.Sprintf("estimate_points = $%d", argId))
+		args = append(args, *update.EstimatePoints)
+		argId++
+	}
+
+	if len(values) != 0 {
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND user_id = $%d",
+			collectionAgenda, strings.Join(values, ", "), argId, argId+1)
+
+		args = append(args, id, userId)
+
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return entity.Task{}, err
+		}
+	}
+
+	if update.Tags != nil {
+		if err = detachTaskTags(ctx, tx, id); err != nil {
+			return entity.Task{}, err
+		}
+
+		if err = attachTaskTags(ctx, tx, id, userId, *update.Tags); err != nil {
+			return entity.Task{}, err
+		}
+	}
+
+	task, err := getTaskByID(ctx, tx, id, userId)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	if changes := taskChanges(&old, task); len(changes) != 0 {
+		err = recordHistory(ctx, tx, entity.HistoryEntry{
+			TaskID:  id,
+			UserID:  userId,
+			Action:  entity.HistoryUpdated,
+			Changes: changes,
+		})
+		if err != nil {
+			return entity.Task{}, err
+		}
+	}
+
+	if next != nil {
+		if err = createNextOccurrence(ctx, tx, *next); err != nil {
+			return entity.Task{}, err
+		}
+	}
+
+	return task, tx.Commit()
+}
+
+// DeleteByID moves the task (and its subtasks) to the trash, all of them get the same deleted_at.
+func (a *AgendaRepository) DeleteByID(ctx context.Context, id int, userId int, withSubtasks bool) error {
 	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
 		ReadOnly:  false,
@@ -122,16 +392,26 @@ func (a *AgendaRepository) DeleteByID(ctx context.Context, id int, userId int) e
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	var query = fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", collectionAgenda)
+	var query = fmt.Sprintf("UPDATE %s SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL RETURNING id",
+		collectionAgenda)
+	if withSubtasks {
+		query = fmt.Sprintf("%s UPDATE %s SET deleted_at = NOW() WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL RETURNING id",
+			subtreeQuery(1, 2), collectionAgenda)
+	}
 
-	_, err = tx.ExecContext(ctx, query, id, userId)
+	ids, err := queryIDs(ctx, tx, query, id, userId)
 	if err != nil {
 		return err
 	}
 
+	if err = recordHistory(ctx, tx, historyEntries(ids, userId, entity.HistoryDeleted)...); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+// DeleteByUserID moves all user tasks to the trash.
 func (a *AgendaRepository) DeleteByUserID(ctx context.Context, userId int) error {
 	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
@@ -142,17 +422,64 @@ func (a *AgendaRepository) DeleteByUserID(ctx context.Context, userId int) error
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	var query = fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", collectionAgenda)
+	var query = fmt.Sprintf("UPDATE %s SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL RETURNING id", collectionAgenda)
 
-	_, err = tx.ExecContext(ctx, query, userId)
+	ids, err := queryIDs(ctx, tx, query, userId)
 	if err != nil {
 		return err
 	}
 
+	if err = recordHistory(ctx, tx, historyEntries(ids, userId, entity.HistoryDeleted)...); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-func (a *AgendaRepository) GetByUserID(ctx context.Context, userId int) ([]entity.Task, error) {
+func (a *AgendaRepository) GetSubtasks(ctx context.Context, id, userId int) ([]entity.Task, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL ORDER BY rank, id",
+		taskColumns, collectionAgenda)
+
+	rows, err := tx.QueryContext(ctx, query, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+// GetByUserID returns the tasks matching the filter, a malformed filter query is reported as *filter.Error.
+func (a *AgendaRepository) GetByUserID(ctx context.Context, userId int, filter entity.TaskFilter) ([]entity.Task, error) {
+	conditions, args := taskFilterConditions(userId, filter)
+
+	condition, args, err := compileFilter(filter.Query, filter.Location, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(condition) != 0 {
+		conditions = append(conditions, condition)
+	}
+
+	keys, err := parseSort(filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelRepeatableRead,
 		ReadOnly:  true,
@@ -162,34 +489,118 @@ func (a *AgendaRepository) GetByUserID(ctx context.Context, userId int) ([]entit
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s %s",
+		taskColumns, collectionAgenda, strings.Join(conditions, " AND "), orderBy(keys))
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+// List returns a page of the tasks matching the filter in the sort order and the number of all of them.
+// A page following the After cursor is found by the sort values of the cursor task, however deep it is.
+// A malformed filter query is reported as *filter.Error.
+func (a *AgendaRepository) List(ctx context.Context, userId int, filter entity.TaskFilter) (entity.TaskPage, error) {
+	conditions, args := taskFilterConditions(userId, filter)
+
+	condition, args, err := compileFilter(filter.Query, filter.Location, args)
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+	if len(condition) != 0 {
+		conditions = append(conditions, condition)
+	}
+
+	keys, err := parseSort(filter.Sort)
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+
+	if filter.After != nil && (filter.After.Sort != filter.Sort || len(filter.After.Values) != len(keys)) {
+		return entity.TaskPage{}, fmt.Errorf("%w: the sort has changed", entity.ErrInvalidCursor)
+	}
+
+	if filter.After != nil {
+		if err = checkCursor(keys, *filter.After); err != nil {
+			return entity.TaskPage{}, err
+		}
+	}
+
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
 	var (
-		tasks []entity.Task
-		query = fmt.Sprintf("SELECT id, title, description, date, status FROM %s WHERE user_id = $1",
-			collectionAgenda)
+		page  entity.TaskPage
+		query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", collectionAgenda, strings.Join(conditions, " AND "))
 	)
 
-	rows, err := tx.QueryContext(ctx, query, userId)
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(&page.Total); err != nil {
+		return entity.TaskPage{}, err
+	}
+
+	if filter.After != nil {
+		var condition string
+		condition, args = afterCursor(keys, *filter.After, args)
+		conditions = append(conditions, condition)
+	}
+
+	// one more task is read to find out whether the next page exists
+	query = fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s %s LIMIT $%d OFFSET $%d", taskColumns, sortValues(keys),
+		collectionAgenda, strings.Join(conditions, " AND "), orderBy(keys), len(args)+1, len(args)+2)
+
+	rows, err := tx.QueryContext(ctx, query, append(args, filter.Limit+1, filter.Offset)...)
 	if err != nil {
-		return nil, err
+		return entity.TaskPage{}, err
 	}
 	defer func() { _ = rows.Close() }()
 
+	var values [][]*string
 	for rows.Next() {
-		var task entity.Task
-		if err = rows.Scan(&task.ID, &task.Title, &task.Description, &task.Date, &task.Status); err != nil {
-			return nil, err
+		var (
+			taskValues = make([]*string, len(keys))
+			dest       = make([]any, 0, len(keys))
+		)
+		for i := range taskValues {
+			dest = append(dest, &taskValues[i])
 		}
-		tasks = append(tasks, task)
+
+		task, err := scanTask(rows, dest...)
+		if err != nil {
+			return entity.TaskPage{}, err
+		}
+		page.Tasks, values = append(page.Tasks, task), append(values, taskValues)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return entity.TaskPage{}, err
 	}
 
-	return tasks, tx.Commit()
+	if len(page.Tasks) > filter.Limit {
+		page.Tasks = page.Tasks[:filter.Limit]
+		page.NextCursor = &entity.TaskCursor{Sort: filter.Sort, Values: values[filter.Limit-1], ID: page.Tasks[filter.Limit-1].ID}
+	}
+
+	return page, tx.Commit()
 }
 
-func (a *AgendaRepository) GetByDateAndStatus(ctx context.Context, userId int, status string, date time.Time, limit, offset int) ([]entity.Task, error) {
+// GetWorkload returns the planned, the completed and the overdue workload of the user per day, from and to are
+// the starts of the first and the last day in the user's location.
+func (a *AgendaRepository) GetWorkload(ctx context.Context, userId int, from, to time.Time) ([]entity.WorkloadDay, error) {
 	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelRepeatableRead,
 		ReadOnly:  true,
@@ -200,39 +611,586 @@ func (a *AgendaRepository) GetByDateAndStatus(ctx context.Context, userId int, s
 	defer func() { _ = tx.Rollback() }()
 
 	var (
-		tasks []entity.Task
-		query string
-		rows  *sql.Rows
+		planned   = fmt.Sprintf("%[1]s.date >= days.start AND %[1]s.date < days.finish", collectionAgenda)
+		completed = fmt.Sprintf("%[1]s.completed_at >= days.start AND %[1]s.completed_at < days.finish", collectionAgenda)
+		overdue   = fmt.Sprintf("%[1]s.date < days.start AND (%[1]s.completed_at IS NULL OR %[1]s.completed_at >= days.start)", collectionAgenda)
+		workload  = func(condition string) string {
+			return fmt.Sprintf("COUNT(%[1]s.id) FILTER (WHERE %[2]s), COALESCE(SUM(%[1]s.estimate_minutes) FILTER (WHERE %[2]s), 0), "+
+				"COALESCE(SUM(%[1]s.estimate_points) FILTER (WHERE %[2]s), 0)", collectionAgenda, condition)
+		}
 	)
 
-	if date.Equal(time.Time{}) {
-		query = fmt.Sprintf(
-			"SELECT id, title, description, date, status FROM %s WHERE user_id = $1 AND status = $2 LIMIT $3 OFFSET $4",
-			collectionAgenda)
-		rows, err = tx.QueryContext(ctx, query, userId, status, limit, offset)
-	} else {
-		query = fmt.Sprintf(
-			"SELECT id, title, description, date, status FROM %s WHERE user_id = $1 AND status = $2 AND DATE(date) = $3 LIMIT $4 OFFSET $5",
-			collectionAgenda)
-		rows, err = tx.QueryContext(ctx, query, userId, status, date, limit, offset)
-	}
+	query := fmt.Sprintf("WITH %[1]s SELECT TO_CHAR(days.date, 'YYYY-MM-DD'), %[3]s, %[4]s, %[5]s FROM days "+
+		"LEFT JOIN %[2]s ON %[2]s.user_id = $1 AND %[2]s.deleted_at IS NULL AND (%[6]s OR %[7]s OR %[8]s) "+
+		"GROUP BY days.date ORDER BY days.date",
+		reportDays, collectionAgenda, workload(planned), workload(completed), workload(overdue), planned, completed, overdue)
 
+	rows, err := tx.QueryContext(ctx, query, userId, from.Format(time.DateOnly), to.Format(time.DateOnly), from.Location().String())
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
 
+	var days []entity.WorkloadDay
 	for rows.Next() {
-		var task entity.Task
-		if err = rows.Scan(&task.ID, &task.Title, &task.Description, &task.Date, &task.Status); err != nil {
+		var day entity.WorkloadDay
+
+		err = rows.Scan(&day.Date, &day.Planned.Tasks, &day.Planned.Minutes, &day.Planned.Points,
+			&day.Completed.Tasks, &day.Completed.Minutes, &day.Completed.Points,
+			&day.Overdue.Tasks, &day.Overdue.Minutes, &day.Overdue.Points)
+		if err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, task)
+		days = append(days, day)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return tasks, tx.Commit()
+	return days, tx.Commit()
+}
+
+// Search returns the tasks of the user (including the archived ones) matching the tsquery, the best matches first.
+func (a *AgendaRepository) Search(ctx context.Context, userId int, query string, limit, offset int) ([]entity.TaskSearchResult, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	search := fmt.Sprintf("SELECT %[1]s, ts_rank(search_vector, query), "+
+		"ts_headline('simple', title, query, '%[3]s, HighlightAll=TRUE'), "+
+		"ts_headline('simple', COALESCE(description, ''), query, '%[3]s, MaxFragments=2, MaxWords=20, MinWords=5') "+
+		"FROM %[2]s, to_tsquery('simple', $2) AS query WHERE user_id = $1 AND deleted_at IS NULL AND search_vector @@ query "+
+		"ORDER BY ts_rank(search_vector, query) DESC, id DESC LIMIT $3 OFFSET $4",
+		taskColumns, collectionAgenda, "StartSel=<mark>, StopSel=</mark>")
+
+	rows, err := tx.QueryContext(ctx, search, userId, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []entity.TaskSearchResult
+	for rows.Next() {
+		var result entity.TaskSearchResult
+		result.Task, err = scanTask(rows, &result.Score, &result.TitleSnippet, &result.DescriptionSnippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, tx.Commit()
+}
+
+// GetArchived returns the archived tasks of the user, the most recently archived first,
+// search (if any) is matched case-insensitively against the title and the description.
+func (a *AgendaRepository) GetArchived(ctx context.Context, userId int, search string, limit, offset int) ([]entity.Task, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		conditions = []string{"user_id = $1", "deleted_at IS NULL", "archived_at IS NOT NULL"}
+		args       = []any{userId}
+	)
+
+	if len(search) != 0 {
+		args = append(args, search)
+		conditions = append(conditions, fmt.Sprintf("(STRPOS(LOWER(title), LOWER($%[1]d)) > 0 OR STRPOS(LOWER(description), LOWER($%[1]d)) > 0)", len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY archived_at DESC, id DESC LIMIT $%d OFFSET $%d",
+		taskColumns, collectionAgenda, strings.Join(conditions, " AND "), len(args)+1, len(args)+2)
+
+	rows, err := tx.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+// Unarchive returns the archived task to the default views.
+func (a *AgendaRepository) Unarchive(ctx context.Context, id, userId int) error {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET archived_at = NULL WHERE id = $1 AND user_id = $2 AND archived_at IS NOT NULL RETURNING id",
+		collectionAgenda)
+
+	ids, err := queryIDs(ctx, tx, query, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = recordHistory(ctx, tx, historyEntries(ids, userId, entity.HistoryUnarchived)...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Archive moves at most limit tasks completed before the time to the archive.
+func (a *AgendaRepository) Archive(ctx context.Context, before time.Time, limit int) (int, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %[1]s SET archived_at = NOW() WHERE id IN (SELECT id FROM %[1]s "+
+		"WHERE completed_at < $1 AND archived_at IS NULL AND deleted_at IS NULL ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED) "+
+		"RETURNING id, user_id", collectionAgenda)
+
+	rows, err := tx.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []entity.HistoryEntry
+	for rows.Next() {
+		entry := entity.HistoryEntry{Action: entity.HistoryArchived}
+		if err = rows.Scan(&entry.TaskID, &entry.UserID); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = closeRows(rows); err != nil {
+		return 0, err
+	}
+
+	if err = recordHistory(ctx, tx, entries...); err != nil {
+		return 0, err
+	}
+
+	return len(entries), tx.Commit()
+}
+
+// GetTrashedByID returns the task from the trash.
+func (a *AgendaRepository) GetTrashedByID(ctx context.Context, id, userId int) (entity.Task, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Task{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		deletedAt *time.Time
+		query     = fmt.Sprintf("SELECT %s, deleted_at FROM %s WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL",
+			taskColumns, collectionAgenda)
+	)
+
+	task, err := scanTask(tx.QueryRowContext(ctx, query, id, userId), &deletedAt)
+	if err != nil {
+		return entity.Task{}, err
+	}
+	task.DeletedAt = deletedAt
+
+	return task, tx.Commit()
+}
+
+// GetTrash returns the user tasks from the trash, recently deleted go first.
+func (a *AgendaRepository) GetTrash(ctx context.Context, userId int) ([]entity.Task, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s, deleted_at FROM %s WHERE user_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id",
+		taskColumns, collectionAgenda)
+
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tasks []entity.Task
+	for rows.Next() {
+		var deletedAt *time.Time
+
+		task, err := scanTask(rows, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+		task.DeletedAt = deletedAt
+
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+// Restore takes the task out of the trash together with the subtasks deleted along with it.
+func (a *AgendaRepository) Restore(ctx context.Context, id, userId int) error {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("%[1]s UPDATE %[2]s SET deleted_at = NULL WHERE id IN (SELECT id FROM subtree) "+
+		"AND deleted_at = (SELECT deleted_at FROM %[2]s WHERE id = $1 AND user_id = $2) RETURNING id",
+		subtreeQuery(1, 2), collectionAgenda)
+
+	ids, err := queryIDs(ctx, tx, query, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = recordHistory(ctx, tx, historyEntries(ids, userId, entity.HistoryRestored)...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge permanently deletes the tasks moved to the trash before the time.
+func (a *AgendaRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1", collectionAgenda)
+
+	result, err := tx.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, tx.Commit()
+}
+
+// createTask inserts the task at the end of the user's manual order.
+func createTask(ctx context.Context, tx *sql.Tx, task entity.Task) (int, error) {
+	if err := lockRanks(ctx, tx, task.UserID); err != nil {
+		return 0, err
+	}
+
+	var (
+		id    int
+		last  string
+		query = fmt.Sprintf("SELECT COALESCE(MAX(rank), '') FROM %s WHERE user_id = $1", collectionAgenda)
+	)
+
+	if err := tx.QueryRowContext(ctx, query, task.UserID).Scan(&last); err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (user_id, title, description, date, status, priority, project_id, parent_id, recurrence, "+
+		"completed_at, estimate_minutes, estimate_points, rank) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id",
+		collectionAgenda)
+
+	err := tx.QueryRowContext(ctx, query, task.UserID, task.Title, task.Description, task.Date, task.Status, task.Priority, task.ProjectID, task.ParentID,
+		task.Recurrence, task.CompletedAt, task.EstimateMinutes, task.EstimatePoints, rank.Between(last, "")).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = attachTaskTags(ctx, tx, id, task.UserID, task.Tags); err != nil {
+		return 0, err
+	}
+
+	err = recordHistory(ctx, tx, entity.HistoryEntry{
+		TaskID:  id,
+		UserID:  task.UserID,
+		Action:  entity.HistoryCreated,
+		Changes: taskChanges(nil, task),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// ranksLockKey is the first key of the advisory locks on the ranks of the user's tasks (the second one is the user id).
+const ranksLockKey = 1
+
+// lockRanks serializes the changes of the user's manual order till the end of the transaction,
+// so the concurrent creates do not get the same rank.
+func lockRanks(ctx context.Context, tx *sql.Tx, userId int) error {
+	_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1, $2)", ranksLockKey, userId)
+	return err
+}
+
+// movedRank returns the rank right before (or after) the target task, the tasks in the trash and the archived ones
+// are skipped, so they neither are the targets nor take the place.
+func movedRank(ctx context.Context, tx *sql.Tx, userId, targetId int, before bool) (string, error) {
+	var (
+		target, neighbour string
+		query             = fmt.Sprintf("SELECT rank FROM %s WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND archived_at IS NULL",
+			collectionAgenda)
+	)
+
+	if err := tx.QueryRowContext(ctx, query, targetId, userId).Scan(&target); err != nil {
+		return "", err
+	}
+
+	query = fmt.Sprintf("SELECT COALESCE(MIN(rank), '') FROM %s WHERE user_id = $1 AND rank > $2 "+
+		"AND deleted_at IS NULL AND archived_at IS NULL", collectionAgenda)
+	if before {
+		query = fmt.Sprintf("SELECT COALESCE(MAX(rank), '') FROM %s WHERE user_id = $1 AND rank < $2 "+
+			"AND deleted_at IS NULL AND archived_at IS NULL", collectionAgenda)
+	}
+
+	if err := tx.QueryRowContext(ctx, query, userId, target).Scan(&neighbour); err != nil {
+		return "", err
+	}
+
+	if before {
+		return rank.Between(neighbour, target), nil
+	}
+	return rank.Between(target, neighbour), nil
+}
+
+// renumberRanks gives all the user's tasks the short ranks keeping their order, it is done once the repeated
+// moves to the same place make the ranks too long.
+func renumberRanks(ctx context.Context, tx *sql.Tx, userId int) error {
+	ids, err := queryIDs(ctx, tx, fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1 ORDER BY rank, id", collectionAgenda), userId)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %[1]s SET rank = renumbered.rank FROM UNNEST($1::INT[], $2::VARCHAR[]) AS renumbered (id, rank) "+
+		"WHERE %[1]s.id = renumbered.id", collectionAgenda)
+
+	_, err = tx.ExecContext(ctx, query, pq.Array(ids), pq.Array(rank.Sequence(len(ids))))
+	return err
+}
+
+// createNextOccurrence inserts the next occurrence of the completed recurring task. The completed occurrences
+// share the title, so it is checked only against the not completed tasks, entity.ErrTaskAlreadyExist is returned if it is taken.
+func createNextOccurrence(ctx context.Context, tx *sql.Tx, next entity.Task) error {
+	var (
+		taken bool
+		query = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE title = $1 AND user_id = $2 AND project_id IS NOT DISTINCT FROM $3 "+
+			"AND completed_at IS NULL AND deleted_at IS NULL)", collectionAgenda)
+	)
+
+	if err := tx.QueryRowContext(ctx, query, next.Title, next.UserID, next.ProjectID).Scan(&taken); err != nil {
+		return err
+	}
+
+	if taken {
+		return entity.ErrTaskAlreadyExist
+	}
+
+	_, err := createTask(ctx, tx, next)
+	return err
+}
+
+func getTaskByID(ctx context.Context, tx *sql.Tx, id, userId int) (entity.Task, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		taskColumns, collectionAgenda)
+
+	return scanTask(tx.QueryRowContext(ctx, query, id, userId))
+}
+
+// completedAtValue returns the assignment of completed_at for the completed flag passed as the placeholder,
+// a task which is not completed anymore leaves the archive.
+func completedAtValue(completedArg int) string {
+	return fmt.Sprintf("completed_at = CASE WHEN $%[1]d THEN COALESCE(completed_at, NOW()) END, "+
+		"archived_at = CASE WHEN $%[1]d THEN archived_at END", completedArg)
+}
+
+// subtreeQuery returns a common table expression "subtree" with ids of the task and all its subtasks,
+// idArg and userIdArg are the numbers of the corresponding query placeholders.
+func subtreeQuery(idArg, userIdArg int) string {
+	return fmt.Sprintf("WITH RECURSIVE subtree AS (SELECT id FROM %[1]s WHERE id = $%[2]d AND user_id = $%[3]d "+
+		"UNION ALL SELECT %[1]s.id FROM %[1]s JOIN subtree ON %[1]s.parent_id = subtree.id)",
+		collectionAgenda, idArg, userIdArg)
+}
+
+// completedStatusQuery returns the first completed status of the workflow applied to the updated task:
+// the statuses of its project if there are any, otherwise the user-wide ones. The default status
+// (the defaultArg placeholder) is used if the workflow has no completed statuses configured.
+func completedStatusQuery(userIdArg, defaultArg int) string {
+	return fmt.Sprintf("COALESCE((SELECT name FROM %[1]s AS workflow WHERE workflow.user_id = $%[3]d AND workflow.completed "+
+		"AND workflow.project_id IS NOT DISTINCT FROM (SELECT project_id FROM %[1]s WHERE user_id = $%[3]d AND project_id = %[2]s.project_id LIMIT 1) "+
+		"ORDER BY workflow.position, workflow.id LIMIT 1), $%[4]d)",
+		collectionStatuses, collectionAgenda, userIdArg, defaultArg)
+}
+
+func attachTaskTags(ctx context.Context, tx *sql.Tx, taskId, userId int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name) SELECT $1, UNNEST($2::VARCHAR[]) ON CONFLICT (user_id, name) DO NOTHING",
+		collectionTags)
+
+	if _, err := tx.ExecContext(ctx, query, userId, pq.Array(tags)); err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (task_id, tag_id) SELECT $1, id FROM %s WHERE user_id = $2 AND name = ANY($3)",
+		collectionAgendaTags, collectionTags)
+
+	_, err := tx.ExecContext(ctx, query, taskId, userId, pq.Array(tags))
+	return err
+}
+
+func detachTaskTags(ctx context.Context, tx *sql.Tx, taskId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE task_id = $1", collectionAgendaTags)
+
+	_, err := tx.ExecContext(ctx, query, taskId)
+	return err
+}
+
+func taskFilterConditions(userId int, filter entity.TaskFilter) ([]string, []any) {
+	var (
+		conditions = []string{"user_id = $1", "deleted_at IS NULL", "archived_at IS NULL"}
+		args       = []any{userId}
+	)
+
+	if len(filter.Status) != 0 {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if filter.Completed != nil {
+		if *filter.Completed {
+			conditions = append(conditions, "completed_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "completed_at IS NULL")
+		}
+	}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("date >= $%d", len(args)))
+	}
+
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("date < $%d", len(args)))
+	}
+
+	if filter.NoDate {
+		conditions = append(conditions, "date IS NULL")
+	}
+
+	if len(filter.Priority) != 0 {
+		args = append(args, filter.Priority)
+		conditions = append(conditions, fmt.Sprintf("priority = $%d", len(args)))
+	}
+
+	if filter.ProjectID != nil {
+		if *filter.ProjectID == 0 {
+			conditions = append(conditions, "project_id IS NULL")
+		} else {
+			args = append(args, *filter.ProjectID)
+			conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+		}
+	}
+
+	if len(filter.Tags) != 0 {
+		args = append(args, pq.Array(filter.Tags))
+		subquery := fmt.Sprintf("SELECT task_id FROM %s WHERE tag_id IN (SELECT id FROM %s WHERE user_id = $1 AND name = ANY($%d))",
+			collectionAgendaTags, collectionTags, len(args))
+
+		if filter.TagsMatchAll {
+			args = append(args, len(filter.Tags))
+			subquery += fmt.Sprintf(" GROUP BY task_id HAVING COUNT(*) = $%d", len(args))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("id IN (%s)", subquery))
+	}
+
+	if filter.Actionable {
+		conditions = append(conditions, fmt.Sprintf("id NOT IN (SELECT %[1]s.task_id FROM %[1]s JOIN %[2]s AS blockers "+
+			"ON blockers.id = %[1]s.blocker_id WHERE blockers.completed_at IS NULL AND blockers.deleted_at IS NULL)", collectionTaskDependencies, collectionAgenda))
+	}
+
+	return conditions, args
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask scans the taskColumns, extra destinations are scanned from the columns selected after them.
+func scanTask(row rowScanner, extra ...any) (entity.Task, error) {
+	var task entity.Task
+
+	dest := []any{&task.ID, &task.Title, &task.Description, &task.Date, &task.Status, &task.Priority, &task.ProjectID, &task.ParentID,
+		&task.Recurrence, &task.CompletedAt, &task.ArchivedAt, &task.EstimateMinutes, &task.EstimatePoints, pq.Array(&task.Tags), &task.TrackedTime}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	return task, nil
+}
+
+func scanTasks(rows *sql.Rows) ([]entity.Task, error) {
+	var tasks []entity.Task
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
 }
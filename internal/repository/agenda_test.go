@@ -2,16 +2,29 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/pkg/rank"
 )
 
+const testTaskColumns = "id, title, description, date, status, priority, project_id, parent_id, recurrence, completed_at, archived_at, " +
+	"estimate_minutes, estimate_points, " +
+	"ARRAY(SELECT tags.name FROM agenda_tags JOIN tags ON tags.id = agenda_tags.tag_id WHERE agenda_tags.task_id = agenda.id ORDER BY tags.name), " +
+	"(SELECT COALESCE(SUM(EXTRACT(EPOCH FROM COALESCE(stopped_at, NOW()) - started_at)::BIGINT), 0) FROM time_entries WHERE time_entries.task_id = agenda.id)"
+
+var testNow = time.Now().Round(time.Second)
+
+var testTaskRows = []string{"id", "title", "description", "date", "status", "priority", "project_id", "parent_id", "recurrence", "completed_at", "archived_at", "estimate_minutes", "estimate_points", "tags", "tracked_time"}
+
 func TestAgendaRepository_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -21,16 +34,2059 @@ func TestAgendaRepository_Create(t *testing.T) {
 
 	repo := NewAgenda(db)
 
-	testTask := entity.Task{
-		UserID:      1,
-		Title:       "Test Task",
-		Description: "Test Description",
-		Date:        time.Now().Round(time.Second),
-		Status:      entity.StatusNotDone,
-	}
+	testTask := entity.Task{
+		UserID:          1,
+		Title:           "Test Task",
+		Description:     "Test Description",
+		Date:            &testNow,
+		Status:          entity.StatusNotDone,
+		Priority:        entity.PriorityHigh,
+		EstimateMinutes: 90,
+	}
+
+	testTaskWithTags := testTask
+	testTaskWithTags.Tags = []string{"work", "errands"}
+
+	type args struct {
+		task entity.Task
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				task: testTask,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.task.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				expectedQuery := "SELECT COALESCE(MAX(rank), '') FROM agenda WHERE user_id = $1"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.task.UserID).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000001V"))
+
+				expectedExec := "INSERT INTO agenda (user_id, title, description, date, status, priority, project_id, parent_id, recurrence, completed_at, estimate_minutes, estimate_points, rank) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.task.UserID, args.task.Title, args.task.Description, args.task.Date, args.task.Status, args.task.Priority, args.task.ProjectID, args.task.ParentID, args.task.Recurrence, args.task.CompletedAt, args.task.EstimateMinutes, args.task.EstimatePoints, "V000000001W").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.task.UserID, entity.HistoryCreated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK with tags",
+			args: args{
+				task: testTaskWithTags,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.task.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				expectedQuery := "SELECT COALESCE(MAX(rank), '') FROM agenda WHERE user_id = $1"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.task.UserID).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000001V"))
+
+				expectedExec := "INSERT INTO agenda (user_id, title, description, date, status, priority, project_id, parent_id, recurrence, completed_at, estimate_minutes, estimate_points, rank) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.task.UserID, args.task.Title, args.task.Description, args.task.Date, args.task.Status, args.task.Priority, args.task.ProjectID, args.task.ParentID, args.task.Recurrence, args.task.CompletedAt, args.task.EstimateMinutes, args.task.EstimatePoints, "V000000001W").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				expectedExec = "INSERT INTO tags (user_id, name) SELECT $1, UNNEST($2::VARCHAR[]) ON CONFLICT (user_id, name) DO NOTHING"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.task.UserID, pq.Array(args.task.Tags)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				expectedExec = "INSERT INTO agenda_tags (task_id, tag_id) SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.task.UserID, pq.Array(args.task.Tags)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.task.UserID, entity.HistoryCreated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				task: entity.Task{},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.task.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				expectedQuery := "SELECT COALESCE(MAX(rank), '') FROM agenda WHERE user_id = $1"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.task.UserID).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000001V"))
+
+				expectedExec := "INSERT INTO agenda (user_id, title, description, date, status, priority, project_id, parent_id, recurrence, completed_at, estimate_minutes, estimate_points, rank) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.task.UserID, args.task.Title, args.task.Description, args.task.Date, args.task.Status, args.task.Priority, args.task.ProjectID, args.task.ParentID, args.task.Recurrence, args.task.CompletedAt, args.task.EstimateMinutes, args.task.EstimatePoints, "V000000001W").
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			_, err := repo.Create(context.Background(), tt.args.task)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	testTaskID := 1
+	testTask := entity.Task{
+		ID:          testTaskID,
+		Title:       "Test Task",
+		Description: "Test Description",
+		Date:        &testNow,
+		Status:      entity.StatusNotDone,
+		Priority:    entity.PriorityNone,
+		Tags:        []string{"work"},
+	}
+
+	type args struct {
+		id     int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantTask      entity.Task
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id: testTaskID,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(testTask.ID, testTask.Title, testTask.Description, testTask.Date, testTask.Status, testTask.Priority, nil, nil, "", nil, nil, 0, 0, "{work}", 0)
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantTask: testTask,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id: testTaskID,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantTask: entity.Task{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			task, err := repo.GetByID(context.Background(), tt.args.id, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTask, task)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_GetByTitle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	testTaskTitle := "Test Task"
+	testProjectID := 1
+	testTask := entity.Task{
+		ID:          1,
+		Title:       testTaskTitle,
+		Description: "Test Description",
+		Date:        &testNow,
+		Status:      entity.StatusNotDone,
+		Priority:    entity.PriorityNone,
+		ProjectID:   &testProjectID,
+		Tags:        []string{"work"},
+	}
+
+	type args struct {
+		title     string
+		userId    int
+		projectId *int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantTask      entity.Task
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				title:     testTaskTitle,
+				projectId: &testProjectID,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(testTask.ID, testTask.Title, testTask.Description, testTask.Date, testTask.Status, testTask.Priority, testProjectID, nil, "", nil, nil, 0, 0, "{work}", 0)
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE title = $1 AND user_id = $2 AND project_id IS NOT DISTINCT FROM $3 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.title, args.userId, args.projectId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantTask: testTask,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				title: testTaskTitle,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE title = $1 AND user_id = $2 AND project_id IS NOT DISTINCT FROM $3 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.title, args.userId, args.projectId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantTask: entity.Task{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			task, err := repo.GetByTitleAndUserID(context.Background(), tt.args.title, tt.args.userId, tt.args.projectId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTask, task)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_SetStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	type args struct {
+		id           int
+		userId       int
+		status       string
+		completed    bool
+		withSubtasks bool
+		next         *entity.Task
+	}
+	type mockBehaviour func(args args)
+
+	testNext := entity.Task{
+		UserID:     1,
+		Title:      "Pay rent",
+		Date:       &testNow,
+		Status:     entity.StatusNotDone,
+		Priority:   entity.PriorityNone,
+		Recurrence: "FREQ=MONTHLY",
+	}
+
+	expectStatusChanged := func(args args) {
+		expectedQuery := "UPDATE agenda SET status = $1, completed_at = CASE WHEN $2 THEN COALESCE(completed_at, NOW()) END, " +
+			"archived_at = CASE WHEN $2 THEN archived_at END " +
+			"FROM (SELECT id, status FROM agenda WHERE id = $3 AND user_id = $4) AS old " +
+			"WHERE agenda.id = old.id RETURNING agenda.id, old.status, agenda.status"
+		mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(args.status, args.completed, args.id, args.userId).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status", "status"}).AddRow(args.id, entity.StatusNotDone, args.status))
+
+		expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+		mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+			WithArgs(args.id, args.userId, entity.HistoryStatusChanged, `{"status":{"old":"not done","new":"done"}}`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	expectedTitleTaken := "SELECT EXISTS (SELECT 1 FROM agenda WHERE title = $1 AND user_id = $2 AND project_id IS NOT DISTINCT FROM $3 " +
+		"AND completed_at IS NULL AND deleted_at IS NULL)"
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+		wantErrIs     error
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:        1,
+				status:    entity.StatusDone,
+				completed: true,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET status = $1, completed_at = CASE WHEN $2 THEN COALESCE(completed_at, NOW()) END, " +
+					"archived_at = CASE WHEN $2 THEN archived_at END " +
+					"FROM (SELECT id, status FROM agenda WHERE id = $3 AND user_id = $4) AS old " +
+					"WHERE agenda.id = old.id RETURNING agenda.id, old.status, agenda.status"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status, args.completed, args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "status"}).AddRow(args.id, entity.StatusNotDone, args.status))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryStatusChanged, `{"status":{"old":"not done","new":"done"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK with subtasks",
+			args: args{
+				id:           1,
+				status:       entity.StatusDone,
+				completed:    true,
+				withSubtasks: true,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "WITH RECURSIVE subtree AS (SELECT id FROM agenda WHERE id = $3 AND user_id = $4 " +
+					"UNION ALL SELECT agenda.id FROM agenda JOIN subtree ON agenda.parent_id = subtree.id) " +
+					"UPDATE agenda SET status = CASE WHEN agenda.id = $3 THEN $1 ELSE " +
+					"COALESCE((SELECT name FROM statuses AS workflow WHERE workflow.user_id = $4 AND workflow.completed " +
+					"AND workflow.project_id IS NOT DISTINCT FROM (SELECT project_id FROM statuses WHERE user_id = $4 AND project_id = agenda.project_id LIMIT 1) " +
+					"ORDER BY workflow.position, workflow.id LIMIT 1), $5) END, " +
+					"completed_at = CASE WHEN $2 THEN COALESCE(completed_at, NOW()) END, " +
+					"archived_at = CASE WHEN $2 THEN archived_at END " +
+					"FROM (SELECT id, status FROM agenda WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL " +
+					"AND (id = $3 OR completed_at IS NULL)) AS old " +
+					"WHERE agenda.id = old.id RETURNING agenda.id, old.status, agenda.status"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status, args.completed, args.id, args.userId, entity.StatusDone).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "status"}).
+						AddRow(1, entity.StatusNotDone, args.status).
+						AddRow(2, "review", "shipped").
+						AddRow(3, "shipped", "shipped"))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.userId, entity.HistoryStatusChanged, `{"status":{"old":"not done","new":"done"}}`,
+						2, args.userId, entity.HistoryStatusChanged, `{"status":{"old":"review","new":"shipped"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK with next occurrence",
+			args: args{
+				id:        1,
+				userId:    1,
+				status:    entity.StatusDone,
+				completed: true,
+				next:      &testNext,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectStatusChanged(args)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTitleTaken)).
+					WithArgs(args.next.Title, args.next.UserID, args.next.ProjectID).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.next.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(rank), '') FROM agenda WHERE user_id = $1")).
+					WithArgs(args.next.UserID).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000001V"))
+
+				expectedQuery := "INSERT INTO agenda (user_id, title, description, date, status, priority, project_id, parent_id, recurrence, completed_at, estimate_minutes, estimate_points, rank) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.next.UserID, args.next.Title, args.next.Description, args.next.Date, args.next.Status, args.next.Priority, args.next.ProjectID,
+						args.next.ParentID, args.next.Recurrence, args.next.CompletedAt, args.next.EstimateMinutes, args.next.EstimatePoints, "V000000001W").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(2, args.next.UserID, entity.HistoryCreated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR next occurrence title is taken",
+			args: args{
+				id:        1,
+				userId:    1,
+				status:    entity.StatusDone,
+				completed: true,
+				next:      &testNext,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectStatusChanged(args)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedTitleTaken)).
+					WithArgs(args.next.Title, args.next.UserID, args.next.ProjectID).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			wantErrIs: entity.ErrTaskAlreadyExist,
+		},
+		{
+			name: "ERROR task does not exist",
+			args: args{
+				id:        3,
+				userId:    1,
+				status:    entity.StatusDone,
+				completed: true,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET status = $1, completed_at = CASE WHEN $2 THEN COALESCE(completed_at, NOW()) END, " +
+					"archived_at = CASE WHEN $2 THEN archived_at END " +
+					"FROM (SELECT id, status FROM agenda WHERE id = $3 AND user_id = $4) AS old " +
+					"WHERE agenda.id = old.id RETURNING agenda.id, old.status, agenda.status"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status, args.completed, args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "status", "status"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr:   true,
+			wantErrIs: sql.ErrNoRows,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				status: entity.StatusNotDone,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET status = $1, completed_at = CASE WHEN $2 THEN COALESCE(completed_at, NOW()) END, " +
+					"archived_at = CASE WHEN $2 THEN archived_at END " +
+					"FROM (SELECT id, status FROM agenda WHERE id = $3 AND user_id = $4) AS old"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status, args.completed, args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.SetStatus(context.Background(), tt.args.id, tt.args.userId, tt.args.status, tt.args.completed, tt.args.withSubtasks, tt.args.next)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_SetProject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	testProjectID := 3
+
+	type args struct {
+		id        int
+		userId    int
+		projectId *int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:        1,
+				userId:    1,
+				projectId: &testProjectID,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET project_id = $1 FROM (SELECT id, project_id FROM agenda WHERE id = $2 AND user_id = $3) AS old " +
+					"WHERE agenda.id = old.id RETURNING old.project_id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.projectId, args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"project_id"}).AddRow(nil))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryMoved, `{"project_id":{"old":null,"new":3}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK without project",
+			args: args{
+				id:     1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET project_id = $1 FROM (SELECT id, project_id FROM agenda WHERE id = $2 AND user_id = $3) AS old " +
+					"WHERE agenda.id = old.id RETURNING old.project_id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"project_id"}).AddRow(testProjectID))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryMoved, `{"project_id":{"old":3,"new":null}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:        2,
+				userId:    1,
+				projectId: &testProjectID,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET project_id = $1 FROM (SELECT id, project_id FROM agenda WHERE id = $2 AND user_id = $3) AS old"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.projectId, args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.SetProject(context.Background(), tt.args.id, tt.args.userId, tt.args.projectId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_Move(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	type args struct {
+		id       int
+		userId   int
+		targetId int
+		before   bool
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK before",
+			args: args{
+				id:       1,
+				userId:   1,
+				targetId: 2,
+				before:   true,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				expectedQuery := "SELECT rank FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND archived_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.targetId, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000003V"))
+
+				expectedQuery = "SELECT COALESCE(MAX(rank), '') FROM agenda WHERE user_id = $1 AND rank < $2 " +
+					"AND deleted_at IS NULL AND archived_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, "V000000003V").
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000002V"))
+
+				expectedQuery = "UPDATE agenda SET rank = $1 FROM (SELECT id, rank FROM agenda WHERE id = $2 AND user_id = $3) AS old " +
+					"WHERE agenda.id = old.id RETURNING old.rank"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("V000000002k", args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000001V"))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryReordered, `{"rank":{"old":"V000000001V","new":"V000000002k"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK after the last task",
+			args: args{
+				id:       1,
+				userId:   1,
+				targetId: 3,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				expectedQuery := "SELECT rank FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND archived_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.targetId, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000003V"))
+
+				expectedQuery = "SELECT COALESCE(MIN(rank), '') FROM agenda WHERE user_id = $1 AND rank > $2 " +
+					"AND deleted_at IS NULL AND archived_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, "V000000003V").
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow(""))
+
+				expectedQuery = "UPDATE agenda SET rank = $1 FROM (SELECT id, rank FROM agenda WHERE id = $2 AND user_id = $3) AS old " +
+					"WHERE agenda.id = old.id RETURNING old.rank"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("V000000003W", args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000001V"))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryReordered, `{"rank":{"old":"V000000001V","new":"V000000003W"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK renumbered",
+			args: args{
+				id:       1,
+				userId:   1,
+				targetId: 2,
+				before:   true,
+			},
+			mockBehaviour: func(args args) {
+				var (
+					long        = "V000000001V" + strings.Repeat("0", rank.MaxLength)
+					targetQuery = "SELECT rank FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND archived_at IS NULL"
+					prevQuery   = "SELECT COALESCE(MAX(rank), '') FROM agenda WHERE user_id = $1 AND rank < $2 " +
+						"AND deleted_at IS NULL AND archived_at IS NULL"
+				)
+
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectQuery(regexp.QuoteMeta(targetQuery)).
+					WithArgs(args.targetId, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow(long + "1"))
+
+				mock.ExpectQuery(regexp.QuoteMeta(prevQuery)).
+					WithArgs(args.userId, long+"1").
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow(long))
+
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM agenda WHERE user_id = $1 ORDER BY rank, id")).
+					WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(2).AddRow(1))
+
+				expectedExec := "UPDATE agenda SET rank = renumbered.rank FROM UNNEST($1::INT[], $2::VARCHAR[]) AS renumbered (id, rank) " +
+					"WHERE agenda.id = renumbered.id"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(pq.Array([]int{3, 2, 1}), pq.Array([]string{"V000000000V", "V000000000W", "V000000000X"})).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+
+				mock.ExpectQuery(regexp.QuoteMeta(targetQuery)).
+					WithArgs(args.targetId, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000000W"))
+
+				mock.ExpectQuery(regexp.QuoteMeta(prevQuery)).
+					WithArgs(args.userId, "V000000000W").
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000000V"))
+
+				expectedQuery := "UPDATE agenda SET rank = $1 FROM (SELECT id, rank FROM agenda WHERE id = $2 AND user_id = $3) AS old " +
+					"WHERE agenda.id = old.id RETURNING old.rank"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("V000000000VV", args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"rank"}).AddRow("V000000000X"))
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryReordered, `{"rank":{"old":"V000000000X","new":"V000000000VV"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:       1,
+				userId:   1,
+				targetId: 4,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1, $2)")).
+					WithArgs(ranksLockKey, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				expectedQuery := "SELECT rank FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL AND archived_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.targetId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Move(context.Background(), tt.args.id, tt.args.userId, tt.args.targetId, tt.args.before)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	var (
+		testTitle       = "New Title"
+		testDescription = "New Description"
+		testDate        = testNow
+		testTags        = []string{"home"}
+		testRecurrence  = "FREQ=WEEKLY;BYDAY=MO"
+	)
+
+	type args struct {
+		id     int
+		userId int
+		update entity.TaskUpdate
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantTask      entity.Task
+		wantErr       bool
+	}{
+		{
+			name: "OK all fields",
+			args: args{
+				id:     1,
+				userId: 1,
+				update: entity.TaskUpdate{
+					Title:       &testTitle,
+					Description: &testDescription,
+					Date:        &testDate,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				oldRows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, "Test Task", "", testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(oldRows)
+
+				expectedExec := "UPDATE agenda SET title = $1, description = $2, date = $3 WHERE id = $4 AND user_id = $5"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testTitle, testDescription, testDate, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, testTitle, testDescription, testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				expectedQuery = "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(rows)
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryUpdated, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantTask: entity.Task{
+				ID:          1,
+				Title:       testTitle,
+				Description: testDescription,
+				Date:        &testDate,
+				Status:      entity.StatusNotDone,
+				Priority:    entity.PriorityNone,
+				Tags:        []string{},
+			},
+		},
+		{
+			name: "OK recurrence",
+			args: args{
+				id:     1,
+				userId: 1,
+				update: entity.TaskUpdate{
+					Recurrence: &testRecurrence,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				oldRows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, "Standup", "", testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(oldRows)
+
+				expectedExec := "UPDATE agenda SET recurrence = $1 WHERE id = $2 AND user_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testRecurrence, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, "Standup", "", testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, testRecurrence, nil, nil, 0, 0, "{}", 0)
+
+				expectedQuery = "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(rows)
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryUpdated, `{"recurrence":{"old":"","new":"FREQ=WEEKLY;BYDAY=MO"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantTask: entity.Task{
+				ID:         1,
+				Title:      "Standup",
+				Date:       &testDate,
+				Status:     entity.StatusNotDone,
+				Priority:   entity.PriorityNone,
+				Recurrence: testRecurrence,
+				Tags:       []string{},
+			},
+		},
+		{
+			name: "OK only tags",
+			args: args{
+				id:     1,
+				userId: 1,
+				update: entity.TaskUpdate{
+					Tags: &testTags,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				oldRows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, "Test Task", "", testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(oldRows)
+
+				expectedExec := "DELETE FROM agenda_tags WHERE task_id = $1"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				expectedExec = "INSERT INTO tags (user_id, name) SELECT $1, UNNEST($2::VARCHAR[]) ON CONFLICT (user_id, name) DO NOTHING"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.userId, pq.Array(testTags)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				expectedExec = "INSERT INTO agenda_tags (task_id, tag_id) SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, pq.Array(testTags)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, "Test Task", "", testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{home}", 0)
+
+				expectedQuery = "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(rows)
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryUpdated, `{"tags":{"old":[],"new":["home"]}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantTask: entity.Task{
+				ID:       1,
+				Title:    "Test Task",
+				Date:     &testDate,
+				Status:   entity.StatusNotDone,
+				Priority: entity.PriorityNone,
+				Tags:     testTags,
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				userId: 1,
+				update: entity.TaskUpdate{
+					Title: &testTitle,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				oldRows := sqlmock.NewRows(testTaskRows).
+					AddRow(args.id, "Test Task", "", testDate, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(oldRows)
+
+				expectedExec := "UPDATE agenda SET title = $1 WHERE id = $2 AND user_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testTitle, args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			task, err := repo.Update(context.Background(), tt.args.id, tt.args.userId, tt.args.update, nil)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTask, task)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	type args struct {
+		id           int
+		userId       int
+		withSubtasks bool
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(args.id))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.userId, entity.HistoryDeleted, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK with subtasks",
+			args: args{
+				id:           1,
+				withSubtasks: true,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "WITH RECURSIVE subtree AS (SELECT id FROM agenda WHERE id = $1 AND user_id = $2 " +
+					"UNION ALL SELECT agenda.id FROM agenda JOIN subtree ON agenda.parent_id = subtree.id) " +
+					"UPDATE agenda SET deleted_at = NOW() WHERE id IN (SELECT id FROM subtree) AND deleted_at IS NULL RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.userId, entity.HistoryDeleted, nil, 2, args.userId, entity.HistoryDeleted, nil).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.userId, tt.args.withSubtasks)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_DeleteByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	type args struct {
+		userID int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userID: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.userID, entity.HistoryDeleted, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userID: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE agenda SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByUserID(context.Background(), tt.args.userID)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_GetSubtasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	var (
+		testDate     = testNow
+		testParentID = 1
+	)
+
+	type args struct {
+		id     int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantTasks     []entity.Task
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     testParentID,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(2, "Subtask 1", "", testDate, "not done", "high", nil, args.id, "", nil, nil, 0, 0, "{}", 0).
+					AddRow(3, "Subtask 2", "", testDate, "done", "none", nil, args.id, "", nil, nil, 0, 0, "{}", 0)
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantTasks: []entity.Task{
+				{ID: 2, Title: "Subtask 1", Date: &testDate, Status: "not done", Priority: "high", ParentID: &testParentID, Tags: []string{}},
+				{ID: 3, Title: "Subtask 2", Date: &testDate, Status: "done", Priority: "none", ParentID: &testParentID, Tags: []string{}},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE parent_id = $1 AND user_id = $2 AND deleted_at IS NULL ORDER BY rank, id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			tasks, err := repo.GetSubtasks(context.Background(), tt.args.id, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTasks, tasks)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_GetByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	type args struct {
+		userID int
+		filter entity.TaskFilter
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name           string
+		args           args
+		mockBehaviour  mockBehaviour
+		wantErr        bool
+		expectedResult []entity.Task
+	}{
+		{
+			name: "OK",
+			args: args{
+				userID: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(0, "Task 1", "Description 1", testNow, "done", "high", nil, nil, "", nil, nil, 0, 0, "{}", 0).
+					AddRow(0, "Task 2", "Description 2", testNow, "not done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: []entity.Task{
+				{
+					Title:       "Task 1",
+					Description: "Description 1",
+					Date:        &testNow,
+					Status:      "done",
+					Priority:    "high",
+					Tags:        []string{},
+				},
+				{
+					Title:       "Task 2",
+					Description: "Description 2",
+					Date:        &testNow,
+					Status:      "not done",
+					Priority:    "none",
+					Tags:        []string{},
+				},
+			},
+		},
+		{
+			name: "OK with priority",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{Priority: entity.PriorityUrgent},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND priority = $2 ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(0, "Task 1", "Description 1", testNow, "done", "urgent", nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.Priority).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: []entity.Task{
+				{
+					Title:       "Task 1",
+					Description: "Description 1",
+					Date:        &testNow,
+					Status:      "done",
+					Priority:    "urgent",
+					Tags:        []string{},
+				},
+			},
+		},
+		{
+			name: "OK without project",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{ProjectID: new(int)},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND project_id IS NULL ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(0, "Task 1", "Description 1", testNow, "done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: []entity.Task{
+				{
+					Title:       "Task 1",
+					Description: "Description 1",
+					Date:        &testNow,
+					Status:      "done",
+					Priority:    "none",
+					Tags:        []string{},
+				},
+			},
+		},
+		{
+			name: "OK actionable",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{Actionable: true},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND id NOT IN " +
+					"(SELECT task_dependencies.task_id FROM task_dependencies JOIN agenda AS blockers " +
+					"ON blockers.id = task_dependencies.blocker_id WHERE blockers.completed_at IS NULL AND blockers.deleted_at IS NULL) ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(0, "Task 1", "Description 1", testNow, "not done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: []entity.Task{
+				{
+					Title:       "Task 1",
+					Description: "Description 1",
+					Date:        &testNow,
+					Status:      "not done",
+					Priority:    "none",
+					Tags:        []string{},
+				},
+			},
+		},
+		{
+			name: "OK with all tags",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{Tags: []string{"work", "home"}, TagsMatchAll: true},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND id IN " +
+					"(SELECT task_id FROM agenda_tags WHERE tag_id IN (SELECT id FROM tags WHERE user_id = $1 AND name = ANY($2)) GROUP BY task_id HAVING COUNT(*) = $3) " +
+					"ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(0, "Task 1", "Description 1", testNow, "done", "none", nil, nil, "", nil, nil, 0, 0, "{home,work}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, pq.Array(args.filter.Tags), len(args.filter.Tags)).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: []entity.Task{
+				{
+					Title:       "Task 1",
+					Description: "Description 1",
+					Date:        &testNow,
+					Status:      "done",
+					Priority:    "none",
+					Tags:        []string{"home", "work"},
+				},
+			},
+		},
+		{
+			name: "OK with filter query",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{Query: `priority>=high due<2024-06-01 (tag:work OR -project:none) "quarterly report"`},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND " +
+					"(((priority >= $2 AND date < $3) AND " +
+					"(id IN (SELECT task_id FROM agenda_tags WHERE tag_id IN (SELECT id FROM tags WHERE user_id = $1 AND name = $4)) OR (project_id IS NULL) IS NOT TRUE)) AND " +
+					"(STRPOS(LOWER(title), LOWER($5)) > 0 OR STRPOS(LOWER(COALESCE(description, '')), LOWER($5)) > 0)) ORDER BY rank, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(0, "Quarterly report", "", testNow, "not done", "urgent", nil, nil, "", nil, nil, 0, 0, "{work}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, "high", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), "work", "quarterly report").
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: []entity.Task{
+				{
+					Title:    "Quarterly report",
+					Date:     &testNow,
+					Status:   "not done",
+					Priority: "urgent",
+					Tags:     []string{"work"},
+				},
+			},
+		},
+		{
+			name: "ERROR invalid filter query",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{Query: "priority>=high colour:red"},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userID: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL ORDER BY rank, id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			tasks, err := repo.GetByUserID(context.Background(), tt.args.userID, tt.args.filter)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, tasks)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	testBerlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("error loading location: %v\n", err)
+	}
+
+	type args struct {
+		userID int
+		filter entity.TaskFilter
+	}
+	type mockBehaviour func(args args)
+
+	var (
+		testRows     = append(testTaskRows, "rank")
+		testRank     = "V000000001V"
+		testNextRank = "V000000002V"
+		testPriority = "high"
+		testDateText = "2026-10-18 10:00:00+00"
+		testNotDate  = "tomorrow"
+		testNotCount = "1e3"
+		testUnknown  = "critical"
+	)
+
+	tests := []struct {
+		name           string
+		args           args
+		mockBehaviour  mockBehaviour
+		wantErr        bool
+		wantErrIs      error
+		expectedResult entity.TaskPage
+	}{
+		{
+			name: "OK with date",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Status: "done",
+					From:   testNow,
+					To:     testNow.AddDate(0, 0, 1),
+					Limit:  10,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status = $2 AND date >= $3 AND date < $4"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE "+conditions)).
+					WithArgs(args.userID, args.filter.Status, args.filter.From, args.filter.To).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", rank::TEXT FROM agenda WHERE " + conditions + " ORDER BY rank, id LIMIT $5 OFFSET $6"
+				rows := sqlmock.NewRows(testRows).
+					AddRow(1, "Task 1", "Description 1", testNow, "done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000001V").
+					AddRow(2, "Task 2", "Description 2", testNow, "done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000002V")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.Status, args.filter.From, args.filter.To, args.filter.Limit+1, args.filter.Offset).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: entity.TaskPage{
+				Tasks: []entity.Task{
+					{ID: 1, Title: "Task 1", Description: "Description 1", Date: &testNow, Status: "done", Priority: "none", Tags: []string{}},
+					{ID: 2, Title: "Task 2", Description: "Description 2", Date: &testNow, Status: "done", Priority: "none", Tags: []string{}},
+				},
+				Total: 2,
+			},
+		},
+		{
+			name: "OK no date",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					NoDate: true,
+					Limit:  10,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND date IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE " + conditions)).
+					WithArgs(args.userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", rank::TEXT FROM agenda WHERE " + conditions + " ORDER BY rank, id LIMIT $2 OFFSET $3"
+				rows := sqlmock.NewRows(testRows).
+					AddRow(1, "Task 1", "Description 1", nil, "not done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000001V")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.Limit+1, args.filter.Offset).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: entity.TaskPage{
+				Tasks: []entity.Task{
+					{ID: 1, Title: "Task 1", Description: "Description 1", Status: "not done", Priority: "none", Tags: []string{}},
+				},
+				Total: 1,
+			},
+		},
+		{
+			name: "OK with filter query in the user's location",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Query:    "due:2026-10-25 -tag:home",
+					Location: testBerlin,
+					Limit:    10,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				// the day of the DST end lasts 25 hours
+				var (
+					start = time.Date(2026, time.October, 25, 0, 0, 0, 0, testBerlin)
+					end   = time.Date(2026, time.October, 26, 0, 0, 0, 0, testBerlin)
+				)
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND " +
+					"((date >= $2 AND date < $3) AND " +
+					"(id IN (SELECT task_id FROM agenda_tags WHERE tag_id IN (SELECT id FROM tags WHERE user_id = $1 AND name = $4))) IS NOT TRUE)"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE "+conditions)).
+					WithArgs(args.userID, start, end, "home").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", rank::TEXT FROM agenda WHERE " + conditions + " ORDER BY rank, id LIMIT $5 OFFSET $6"
+				rows := sqlmock.NewRows(testRows).
+					AddRow(1, "Task 1", "Description 1", testNow, "not done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000001V")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, start, end, "home", args.filter.Limit+1, args.filter.Offset).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: entity.TaskPage{
+				Tasks: []entity.Task{
+					{ID: 1, Title: "Task 1", Description: "Description 1", Date: &testNow, Status: "not done", Priority: "none", Tags: []string{}},
+				},
+				Total: 1,
+			},
+		},
+		{
+			name: "ERROR invalid filter query",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Query: "due<tomorrow",
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+		},
+		{
+			name: "OK after cursor",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Status: "done",
+					After:  &entity.TaskCursor{Values: []*string{&testRank}, ID: 1},
+					Limit:  1,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status = $2"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE "+conditions)).
+					WithArgs(args.userID, args.filter.Status).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", rank::TEXT FROM agenda WHERE " + conditions +
+					" AND (rank, id) > ($3, $4) ORDER BY rank, id LIMIT $5 OFFSET $6"
+				rows := sqlmock.NewRows(testRows).
+					AddRow(2, "Task 2", "Description 2", nil, "done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000002V").
+					AddRow(3, "Task 3", "Description 3", nil, "done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000003V")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.Status, *args.filter.After.Values[0], args.filter.After.ID, args.filter.Limit+1, args.filter.Offset).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: entity.TaskPage{
+				Tasks: []entity.Task{
+					{ID: 2, Title: "Task 2", Description: "Description 2", Status: "done", Priority: "none", Tags: []string{}},
+				},
+				Total:      3,
+				NextCursor: &entity.TaskCursor{Values: []*string{&testNextRank}, ID: 2},
+			},
+		},
+		{
+			name: "OK sorted after cursor",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "-priority,date",
+					After: &entity.TaskCursor{Sort: "-priority,date", Values: []*string{&testPriority, &testDateText}, ID: 1},
+					Limit: 1,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE " + conditions)).
+					WithArgs(args.userID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", priority::TEXT, date::TEXT FROM agenda WHERE " + conditions +
+					" AND (priority < $4 OR (priority = $4 AND (date > $3 OR date IS NULL OR (date = $3 AND id > $2)))) " +
+					"ORDER BY priority DESC, date NULLS LAST, id LIMIT $5 OFFSET $6"
+				rows := sqlmock.NewRows(append(testTaskRows, "priority", "date")).
+					AddRow(2, "Task 2", "Description 2", nil, "not done", "high", nil, nil, "", nil, nil, 0, 0, "{}", 0, "high", nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.After.ID, *args.filter.After.Values[1], *args.filter.After.Values[0], args.filter.Limit+1, args.filter.Offset).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: entity.TaskPage{
+				Tasks: []entity.Task{
+					{ID: 2, Title: "Task 2", Description: "Description 2", Status: "not done", Priority: "high", Tags: []string{}},
+				},
+				Total: 2,
+			},
+		},
+		{
+			name: "OK with priority and tags",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Status:   "not done",
+					Priority: "high",
+					Tags:     []string{"work"},
+					Limit:    5,
+					Offset:   5,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status = $2 AND priority = $3 AND id IN " +
+					"(SELECT task_id FROM agenda_tags WHERE tag_id IN (SELECT id FROM tags WHERE user_id = $1 AND name = ANY($4)))"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE "+conditions)).
+					WithArgs(args.userID, args.filter.Status, args.filter.Priority, pq.Array(args.filter.Tags)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", rank::TEXT FROM agenda WHERE " + conditions + " ORDER BY rank, id LIMIT $5 OFFSET $6"
+				rows := sqlmock.NewRows(testRows).
+					AddRow(6, "Task 6", "Description 6", nil, "not done", "high", nil, nil, "", nil, nil, 0, 0, "{}", 0, "V000000006V")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.Status, args.filter.Priority, pq.Array(args.filter.Tags), args.filter.Limit+1, args.filter.Offset).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			expectedResult: entity.TaskPage{
+				Tasks: []entity.Task{
+					{ID: 6, Title: "Task 6", Description: "Description 6", Status: "not done", Priority: "high", Tags: []string{}},
+				},
+				Total: 6,
+			},
+		},
+		{
+			name: "ERROR invalid sort",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "-priority,description",
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+		},
+		{
+			name: "ERROR cursor of another sort",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "title",
+					After: &entity.TaskCursor{Values: []*string{&testRank}, ID: 1},
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+			wantErrIs:     entity.ErrInvalidCursor,
+		},
+		{
+			name: "ERROR cursor with invalid date",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "date",
+					After: &entity.TaskCursor{Sort: "date", Values: []*string{&testNotDate}, ID: 1},
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+			wantErrIs:     entity.ErrInvalidCursor,
+		},
+		{
+			name: "ERROR cursor with invalid number",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "estimate_minutes",
+					After: &entity.TaskCursor{Sort: "estimate_minutes", Values: []*string{&testNotCount}, ID: 1},
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+			wantErrIs:     entity.ErrInvalidCursor,
+		},
+		{
+			name: "ERROR cursor with unknown priority",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "priority",
+					After: &entity.TaskCursor{Sort: "priority", Values: []*string{&testUnknown}, ID: 1},
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+			wantErrIs:     entity.ErrInvalidCursor,
+		},
+		{
+			name: "ERROR cursor with null title",
+			args: args{
+				userID: 1,
+				filter: entity.TaskFilter{
+					Sort:  "title",
+					After: &entity.TaskCursor{Sort: "title", Values: []*string{nil}, ID: 1},
+					Limit: 10,
+				},
+			},
+			mockBehaviour: func(args args) {},
+			wantErr:       true,
+			wantErrIs:     entity.ErrInvalidCursor,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userID: 2,
+				filter: entity.TaskFilter{
+					Status: "done",
+					Limit:  10,
+				},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				conditions := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status = $2"
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM agenda WHERE "+conditions)).
+					WithArgs(args.userID, args.filter.Status).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+				expectedQuery := "SELECT " + testTaskColumns + ", rank::TEXT FROM agenda WHERE " + conditions + " ORDER BY rank, id LIMIT $3 OFFSET $4"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userID, args.filter.Status, args.filter.Limit+1, args.filter.Offset).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			page, err := repo.List(context.Background(), tt.args.userID, tt.args.filter)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, page)
+			}
+		})
+	}
+}
+
+func TestAgendaRepository_GetWorkload(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAgenda(db)
+
+	var (
+		testFrom      = time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+		testTo        = time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC)
+		expectedQuery = "WITH days AS (SELECT day::DATE AS date, day AT TIME ZONE $4 AS start, (day + INTERVAL '1 day') AT TIME ZONE $4 AS finish " +
+			"FROM GENERATE_SERIES($2::DATE, $3::DATE, INTERVAL '1 day') AS day) " +
+			"SELECT TO_CHAR(days.date, 'YYYY-MM-DD'), " +
+			"COUNT(agenda.id) FILTER (WHERE agenda.date >= days.start AND agenda.date < days.finish), " +
+			"COALESCE(SUM(agenda.estimate_minutes) FILTER (WHERE agenda.date >= days.start AND agenda.date < days.finish), 0), " +
+			"COALESCE(SUM(agenda.estimate_points) FILTER (WHERE agenda.date >= days.start AND agenda.date < days.finish), 0), " +
+			"COUNT(agenda.id) FILTER (WHERE agenda.completed_at >= days.start AND agenda.completed_at < days.finish)"
+		expectedJoin = "FROM days LEFT JOIN agenda ON agenda.user_id = $1 AND agenda.deleted_at IS NULL AND " +
+			"(agenda.date >= days.start AND agenda.date < days.finish OR agenda.completed_at >= days.start AND agenda.completed_at < days.finish OR " +
+			"agenda.date < days.start AND (agenda.completed_at IS NULL OR agenda.completed_at >= days.start)) GROUP BY days.date ORDER BY days.date"
+		testWorkloadRows = []string{"date", "planned_tasks", "planned_minutes", "planned_points", "completed_tasks", "completed_minutes",
+			"completed_points", "overdue_tasks", "overdue_minutes", "overdue_points"}
+	)
 
 	type args struct {
-		task entity.Task
+		userId int
+		from   time.Time
+		to     time.Time
 	}
 	type mockBehaviour func(args args)
 
@@ -38,35 +2094,55 @@ func TestAgendaRepository_Create(t *testing.T) {
 		name          string
 		args          args
 		mockBehaviour mockBehaviour
+		want          []entity.WorkloadDay
 		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				task: testTask,
+				userId: 1,
+				from:   testFrom,
+				to:     testTo,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO agenda (user_id, title, description, date, status) VALUES ($1, $2, $3, $4, $5) RETURNING id"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.task.UserID, args.task.Title, args.task.Description, args.task.Date, args.task.Status).
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				rows := sqlmock.NewRows(testWorkloadRows).
+					AddRow("2026-10-05", 3, 240, 5, 2, 120, 3, 0, 0, 0).
+					AddRow("2026-10-06", 2, 90, 0, 1, 30, 0, 1, 120, 2)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)+".*"+regexp.QuoteMeta(expectedJoin)).
+					WithArgs(args.userId, "2026-10-05", "2026-10-06", "UTC").
+					WillReturnRows(rows)
 
 				mock.ExpectCommit()
 			},
+			want: []entity.WorkloadDay{
+				{
+					Date:      "2026-10-05",
+					Planned:   entity.Workload{Tasks: 3, Minutes: 240, Points: 5},
+					Completed: entity.Workload{Tasks: 2, Minutes: 120, Points: 3},
+				},
+				{
+					Date:      "2026-10-06",
+					Planned:   entity.Workload{Tasks: 2, Minutes: 90},
+					Completed: entity.Workload{Tasks: 1, Minutes: 30},
+					Overdue:   entity.Workload{Tasks: 1, Minutes: 120, Points: 2},
+				},
+			},
 		},
 		{
 			name: "ERROR",
 			args: args{
-				task: entity.Task{},
+				userId: 2,
+				from:   testFrom,
+				to:     testTo,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO agenda (user_id, title, description, date, status) VALUES ($1, $2, $3, $4, $5) RETURNING id"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.task.UserID, args.task.Title, args.task.Description, args.task.Date, args.task.Status).
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, "2026-10-05", "2026-10-06", "UTC").
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
@@ -78,18 +2154,19 @@ func TestAgendaRepository_Create(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			_, err := repo.Create(context.Background(), tt.args.task)
+			got, err := repo.GetWorkload(context.Background(), tt.args.userId, tt.args.from, tt.args.to)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_GetByID(t *testing.T) {
+func TestAgendaRepository_GetTrash(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -98,16 +2175,12 @@ func TestAgendaRepository_GetByID(t *testing.T) {
 
 	repo := NewAgenda(db)
 
-	testTaskID := 1
-	testTask := entity.Task{
-		Title:       "Test Task",
-		Description: "Test Description",
-		Date:        time.Now().Round(time.Second),
-		Status:      entity.StatusNotDone,
-	}
+	var (
+		testDate      = testNow
+		testDeletedAt = testDate.Add(time.Hour)
+	)
 
 	type args struct {
-		id     int
 		userId int
 	}
 	type mockBehaviour func(args args)
@@ -116,62 +2189,68 @@ func TestAgendaRepository_GetByID(t *testing.T) {
 		name          string
 		args          args
 		mockBehaviour mockBehaviour
-		wantTask      entity.Task
+		want          []entity.Task
 		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				id: testTaskID,
+				userId: 1,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"title", "description", "date", "status"}).
-					AddRow(testTask.Title, testTask.Description, testTask.Date, testTask.Status)
+				expectedQuery := "SELECT " + testTaskColumns + ", deleted_at FROM agenda WHERE user_id = $1 AND deleted_at IS NOT NULL " +
+					"ORDER BY deleted_at DESC, id"
+				rows := sqlmock.NewRows(append(testTaskRows, "deleted_at")).
+					AddRow(1, "Task 1", "", testDate, "not done", "none", nil, nil, "", nil, nil, 0, 0, "{}", 0, testDeletedAt)
 
-				expectedQuery := "SELECT title, description, date, status FROM agenda WHERE id = $1 AND user_id = $2"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnRows(rows)
 
 				mock.ExpectCommit()
 			},
-			wantTask: testTask,
+			want: []entity.Task{
+				{ID: 1, Title: "Task 1", Date: &testDate, Status: "not done", Priority: "none", DeletedAt: &testDeletedAt, Tags: []string{}},
+			},
 		},
 		{
 			name: "ERROR",
 			args: args{
-				id: testTaskID,
+				userId: 1,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "SELECT title, description, date, status FROM agenda WHERE id = $1 AND user_id = $2"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).
+				expectedQuery := "SELECT " + testTaskColumns + ", deleted_at FROM agenda WHERE user_id = $1 AND deleted_at IS NOT NULL " +
+					"ORDER BY deleted_at DESC, id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
 			},
-			wantTask: entity.Task{},
-			wantErr:  true,
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			task, err := repo.GetByID(context.Background(), tt.args.id, tt.args.userId)
+			got, err := repo.GetTrash(context.Background(), tt.args.userId)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.wantTask, task)
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_GetByTitle(t *testing.T) {
+func TestAgendaRepository_Restore(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -180,16 +2259,13 @@ func TestAgendaRepository_GetByTitle(t *testing.T) {
 
 	repo := NewAgenda(db)
 
-	testTaskTitle := "Test Task"
-	testTask := entity.Task{
-		Title:       testTaskTitle,
-		Description: "Test Description",
-		Date:        time.Now().Round(time.Second),
-		Status:      entity.StatusNotDone,
-	}
+	expectedQuery := "WITH RECURSIVE subtree AS (SELECT id FROM agenda WHERE id = $1 AND user_id = $2 " +
+		"UNION ALL SELECT agenda.id FROM agenda JOIN subtree ON agenda.parent_id = subtree.id) " +
+		"UPDATE agenda SET deleted_at = NULL WHERE id IN (SELECT id FROM subtree) " +
+		"AND deleted_at = (SELECT deleted_at FROM agenda WHERE id = $1 AND user_id = $2) RETURNING id"
 
 	type args struct {
-		title  string
+		id     int
 		userId int
 	}
 	type mockBehaviour func(args args)
@@ -198,62 +2274,63 @@ func TestAgendaRepository_GetByTitle(t *testing.T) {
 		name          string
 		args          args
 		mockBehaviour mockBehaviour
-		wantTask      entity.Task
 		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				title: testTaskTitle,
+				id:     1,
+				userId: 1,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"title", "description", "date", "status"}).
-					AddRow(testTask.Title, testTask.Description, testTask.Date, testTask.Status)
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
-				expectedQuery := "SELECT title, description, date, status FROM agenda WHERE title = $1 AND user_id = $2"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.title, args.userId).WillReturnRows(rows)
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.userId, entity.HistoryRestored, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 
 				mock.ExpectCommit()
 			},
-			wantTask: testTask,
 		},
 		{
 			name: "ERROR",
 			args: args{
-				title: testTaskTitle,
+				id:     1,
+				userId: 1,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "SELECT title, description, date, status FROM agenda WHERE title = $1 AND user_id = $2"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.title, args.userId).
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
 			},
-			wantTask: entity.Task{},
-			wantErr:  true,
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			task, err := repo.GetByTitleAndUserID(context.Background(), tt.args.title, tt.args.userId)
+			err := repo.Restore(context.Background(), tt.args.id, tt.args.userId)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.wantTask, task)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_SetStatus(t *testing.T) {
+func TestAgendaRepository_Purge(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -263,9 +2340,7 @@ func TestAgendaRepository_SetStatus(t *testing.T) {
 	repo := NewAgenda(db)
 
 	type args struct {
-		id     int
-		userId int
-		status string
+		before time.Time
 	}
 	type mockBehaviour func(args args)
 
@@ -273,37 +2348,35 @@ func TestAgendaRepository_SetStatus(t *testing.T) {
 		name          string
 		args          args
 		mockBehaviour mockBehaviour
+		want          int64
 		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				id:     1,
-				status: entity.StatusDone,
+				before: testNow,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "UPDATE agenda SET status = $1 WHERE id = $2 AND user_id = $3"
-				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.status, args.id, args.userId).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM agenda WHERE deleted_at < $1")).
+					WithArgs(args.before).
+					WillReturnResult(sqlmock.NewResult(0, 5))
 
 				mock.ExpectCommit()
 			},
+			want: 5,
 		},
 		{
 			name: "ERROR",
 			args: args{
-				id:     2,
-				status: entity.StatusNotDone,
+				before: testNow,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "UPDATE agenda SET status = $1 WHERE id = $2 AND user_id = $3"
-				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.status, args.id, args.userId).
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM agenda WHERE deleted_at < $1")).
+					WithArgs(args.before).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
@@ -315,18 +2388,19 @@ func TestAgendaRepository_SetStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			err := repo.SetStatus(context.Background(), tt.args.id, tt.args.userId, tt.args.status)
+			got, err := repo.Purge(context.Background(), tt.args.before)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_DeleteByID(t *testing.T) {
+func TestAgendaRepository_Search(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -335,9 +2409,17 @@ func TestAgendaRepository_DeleteByID(t *testing.T) {
 
 	repo := NewAgenda(db)
 
+	expectedQuery := "SELECT " + testTaskColumns + ", ts_rank(search_vector, query), " +
+		"ts_headline('simple', title, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=TRUE'), " +
+		"ts_headline('simple', COALESCE(description, ''), query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') " +
+		"FROM agenda, to_tsquery('simple', $2) AS query WHERE user_id = $1 AND deleted_at IS NULL AND search_vector @@ query " +
+		"ORDER BY ts_rank(search_vector, query) DESC, id DESC LIMIT $3 OFFSET $4"
+
 	type args struct {
-		id     int
 		userId int
+		query  string
+		limit  int
+		offset int
 	}
 	type mockBehaviour func(args args)
 
@@ -345,35 +2427,49 @@ func TestAgendaRepository_DeleteByID(t *testing.T) {
 		name          string
 		args          args
 		mockBehaviour mockBehaviour
+		want          []entity.TaskSearchResult
 		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				id: 1,
+				userId: 1,
+				query:  "invoice:*",
+				limit:  20,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM agenda WHERE id = $1 AND user_id = $2"
-				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.id, args.userId).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, args.query, args.limit, args.offset).
+					WillReturnRows(sqlmock.NewRows(append(testTaskRows, "ts_rank", "title_snippet", "description_snippet")).
+						AddRow(1, "Pay invoice", "Invoices for March", nil, entity.StatusNotDone, entity.PriorityNone, nil, nil, "", nil, nil, 0, 0, "{}", 0,
+							0.6, "Pay <mark>invoice</mark>", "<mark>Invoices</mark> for March"))
 
 				mock.ExpectCommit()
 			},
+			want: []entity.TaskSearchResult{
+				{
+					Task: entity.Task{ID: 1, Title: "Pay invoice", Description: "Invoices for March", Status: entity.StatusNotDone,
+						Priority: entity.PriorityNone, Tags: []string{}},
+					Score:              0.6,
+					TitleSnippet:       "Pay <mark>invoice</mark>",
+					DescriptionSnippet: "<mark>Invoices</mark> for March",
+				},
+			},
 		},
 		{
 			name: "ERROR",
 			args: args{
-				id: 2,
+				userId: 1,
+				query:  "invoice:*",
+				limit:  20,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM agenda WHERE id = $1 AND user_id = $2"
-				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.id, args.userId).
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, args.query, args.limit, args.offset).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
@@ -385,18 +2481,19 @@ func TestAgendaRepository_DeleteByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.userId)
+			got, err := repo.Search(context.Background(), tt.args.userId, tt.args.query, tt.args.limit, tt.args.offset)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_DeleteByUserID(t *testing.T) {
+func TestAgendaRepository_GetArchived(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -406,28 +2503,62 @@ func TestAgendaRepository_DeleteByUserID(t *testing.T) {
 	repo := NewAgenda(db)
 
 	type args struct {
-		userID int
+		userId int
+		search string
+		limit  int
+		offset int
 	}
 	type mockBehaviour func(args args)
 
+	archivedAt := testNow
+
 	tests := []struct {
 		name          string
 		args          args
 		mockBehaviour mockBehaviour
+		want          []entity.Task
 		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				userID: 1,
+				userId: 1,
+				limit:  10,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM agenda WHERE user_id = $1"
-				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID).
-					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NOT NULL " +
+					"ORDER BY archived_at DESC, id DESC LIMIT $2 OFFSET $3"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, args.limit, args.offset).
+					WillReturnRows(sqlmock.NewRows(testTaskRows).
+						AddRow(1, "Task 1", "Description 1", nil, entity.StatusDone, entity.PriorityNone, nil, nil, "", archivedAt, archivedAt, 0, 0, "{}", 0))
+
+				mock.ExpectCommit()
+			},
+			want: []entity.Task{
+				{ID: 1, Title: "Task 1", Description: "Description 1", Status: entity.StatusDone, Priority: entity.PriorityNone,
+					CompletedAt: &archivedAt, ArchivedAt: &archivedAt, Tags: []string{}},
+			},
+		},
+		{
+			name: "OK with search",
+			args: args{
+				userId: 1,
+				search: "report",
+				limit:  10,
+				offset: 10,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NOT NULL " +
+					"AND (STRPOS(LOWER(title), LOWER($2)) > 0 OR STRPOS(LOWER(description), LOWER($2)) > 0) " +
+					"ORDER BY archived_at DESC, id DESC LIMIT $3 OFFSET $4"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, args.search, args.limit, args.offset).
+					WillReturnRows(sqlmock.NewRows(testTaskRows))
 
 				mock.ExpectCommit()
 			},
@@ -435,14 +2566,14 @@ func TestAgendaRepository_DeleteByUserID(t *testing.T) {
 		{
 			name: "ERROR",
 			args: args{
-				userID: 2,
+				userId: 1,
+				limit:  10,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM agenda WHERE user_id = $1"
-				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+testTaskColumns+" FROM agenda")).
+					WithArgs(args.userId, args.limit, args.offset).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
@@ -454,18 +2585,19 @@ func TestAgendaRepository_DeleteByUserID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			err := repo.DeleteByUserID(context.Background(), tt.args.userID)
+			got, err := repo.GetArchived(context.Background(), tt.args.userId, tt.args.search, tt.args.limit, tt.args.offset)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_GetByUserID(t *testing.T) {
+func TestAgendaRepository_Unarchive(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -474,63 +2606,52 @@ func TestAgendaRepository_GetByUserID(t *testing.T) {
 
 	repo := NewAgenda(db)
 
+	expectedQuery := "UPDATE agenda SET archived_at = NULL WHERE id = $1 AND user_id = $2 AND archived_at IS NOT NULL RETURNING id"
+
 	type args struct {
-		userID int
+		id     int
+		userId int
 	}
 	type mockBehaviour func(args args)
 
 	tests := []struct {
-		name           string
-		args           args
-		mockBehaviour  mockBehaviour
-		wantErr        bool
-		expectedResult []entity.Task
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
 	}{
 		{
 			name: "OK",
 			args: args{
-				userID: 1,
+				id:     1,
+				userId: 1,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "SELECT id, title, description, date, status FROM agenda WHERE user_id = $1"
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "date", "status"}).
-					AddRow(0, "Task 1", "Description 1", time.Now().Round(time.Second), "done").
-					AddRow(0, "Task 2", "Description 2", time.Now().Round(time.Second), "not done")
-
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID).
-					WillReturnRows(rows)
+					WithArgs(args.id, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.userId, entity.HistoryUnarchived, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 
 				mock.ExpectCommit()
 			},
-			expectedResult: []entity.Task{
-				{
-					Title:       "Task 1",
-					Description: "Description 1",
-					Date:        time.Now().Round(time.Second),
-					Status:      "done",
-				},
-				{
-					Title:       "Task 2",
-					Description: "Description 2",
-					Date:        time.Now().Round(time.Second),
-					Status:      "not done",
-				},
-			},
 		},
 		{
 			name: "ERROR",
 			args: args{
-				userID: 2,
+				id:     1,
+				userId: 1,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "SELECT id, title, description, date, status FROM agenda WHERE user_id = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID).
+					WithArgs(args.id, args.userId).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
@@ -542,19 +2663,18 @@ func TestAgendaRepository_GetByUserID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			tasks, err := repo.GetByUserID(context.Background(), tt.args.userID)
+			err := repo.Unarchive(context.Background(), tt.args.id, tt.args.userId)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, tasks)
 			}
 		})
 	}
 }
 
-func TestAgendaRepository_GetByDateAndStatus(t *testing.T) {
+func TestAgendaRepository_Archive(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("error creating database connection: %v\n", err)
@@ -563,113 +2683,56 @@ func TestAgendaRepository_GetByDateAndStatus(t *testing.T) {
 
 	repo := NewAgenda(db)
 
+	expectedQuery := "UPDATE agenda SET archived_at = NOW() WHERE id IN (SELECT id FROM agenda " +
+		"WHERE completed_at < $1 AND archived_at IS NULL AND deleted_at IS NULL ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING id, user_id"
+
 	type args struct {
-		userID int
-		status string
-		date   time.Time
+		before time.Time
 		limit  int
-		offset int
 	}
 	type mockBehaviour func(args args)
 
 	tests := []struct {
-		name           string
-		args           args
-		mockBehaviour  mockBehaviour
-		wantErr        bool
-		expectedResult []entity.Task
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          int
+		wantErr       bool
 	}{
 		{
-			name: "OK with date",
+			name: "OK",
 			args: args{
-				userID: 1,
-				status: "completed",
-				date:   time.Now().Round(time.Second),
-				limit:  10,
-				offset: 0,
+				before: testNow,
+				limit:  100,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "SELECT id, title, description, date, status FROM agenda WHERE user_id = $1 AND status = $2 AND DATE(date) = $3 LIMIT $4 OFFSET $5"
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "date", "status"}).
-					AddRow(0, "Task 1", "Description 1", time.Now().Round(time.Second), "done").
-					AddRow(0, "Task 2", "Description 2", time.Now().Round(time.Second), "done")
-
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID, args.status, args.date, args.limit, args.offset).
-					WillReturnRows(rows)
-
-				mock.ExpectCommit()
-			},
-			expectedResult: []entity.Task{
-				{
-					Title:       "Task 1",
-					Description: "Description 1",
-					Date:        time.Now().Round(time.Second),
-					Status:      "done",
-				},
-				{
-					Title:       "Task 2",
-					Description: "Description 2",
-					Date:        time.Now().Round(time.Second),
-					Status:      "done",
-				},
-			},
-		},
-		{
-			name: "OK without date",
-			args: args{
-				userID: 1,
-				status: "completed",
-				date:   time.Time{},
-				limit:  10,
-				offset: 0,
-			},
-			mockBehaviour: func(args args) {
-				mock.ExpectBegin()
+					WithArgs(args.before, args.limit).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, 1).AddRow(2, 3))
 
-				expectedQuery := "SELECT id, title, description, date, status FROM agenda WHERE user_id = $1 AND status = $2 LIMIT $3 OFFSET $4"
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "date", "status"}).
-					AddRow(0, "Task 1", "Description 1", time.Time{}, "done").
-					AddRow(0, "Task 2", "Description 2", time.Time{}, "done")
-
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID, args.status, args.limit, args.offset).
-					WillReturnRows(rows)
+				expectedExec := "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, 1, entity.HistoryArchived, nil, 2, 3, entity.HistoryArchived, nil).
+					WillReturnResult(sqlmock.NewResult(0, 2))
 
 				mock.ExpectCommit()
 			},
-			expectedResult: []entity.Task{
-				{
-					Title:       "Task 1",
-					Description: "Description 1",
-					Date:        time.Time{},
-					Status:      "done",
-				},
-				{
-					Title:       "Task 2",
-					Description: "Description 2",
-					Date:        time.Time{},
-					Status:      "done",
-				},
-			},
+			want: 2,
 		},
 		{
 			name: "ERROR",
 			args: args{
-				userID: 2,
-				status: "completed",
-				date:   time.Now().Round(time.Second),
-				limit:  10,
-				offset: 0,
+				before: testNow,
+				limit:  100,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "SELECT id, title, description, date, status FROM agenda WHERE user_id = $1 AND status = $2 AND DATE(date) = $3 LIMIT $4 OFFSET $5"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.userID, args.status, args.date, args.limit, args.offset).
+					WithArgs(args.before, args.limit).
 					WillReturnError(errors.New("test error"))
 
 				mock.ExpectRollback()
@@ -681,13 +2744,13 @@ func TestAgendaRepository_GetByDateAndStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
-			tasks, err := repo.GetByDateAndStatus(context.Background(), tt.args.userID, tt.args.status, tt.args.date, tt.args.limit, tt.args.offset)
+			got, err := repo.Archive(context.Background(), tt.args.before, tt.args.limit)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedResult, tasks)
+				assert.Equal(t, tt.want, got)
 			}
 		})
 	}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type AttachmentsRepository struct {
+	db *sql.DB
+}
+
+func NewAttachments(db *sql.DB) *AttachmentsRepository {
+	return &AttachmentsRepository{db: db}
+}
+
+// Create saves the attachment unless the attachments of the user would exceed the quota (in bytes),
+// in that case nothing is created and sql.ErrNoRows is returned.
+func (a *AttachmentsRepository) Create(ctx context.Context, attachment entity.Attachment, quota int64) (int, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %[1]s (task_id, user_id, filename, content_type, size, storage_key) "+
+			"SELECT $1, $2, $3, $4, $5, $6 WHERE (SELECT COALESCE(SUM(size), 0) FROM %[1]s WHERE user_id = $2) + $5 <= $7 RETURNING id",
+			collectionAttachments)
+	)
+
+	err = tx.QueryRowContext(ctx, query, attachment.TaskID, attachment.UserID, attachment.Filename, attachment.ContentType,
+		attachment.Size, attachment.StorageKey, quota).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (a *AttachmentsRepository) GetByID(ctx context.Context, id, taskId, userId int) (entity.Attachment, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, user_id, filename, content_type, size, storage_key, created_at FROM %s "+
+		"WHERE id = $1 AND task_id = $2 AND user_id = $3", collectionAttachments)
+
+	attachment, err := scanAttachment(tx.QueryRowContext(ctx, query, id, taskId, userId))
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	return attachment, tx.Commit()
+}
+
+// GetUsedSize returns the total size of the user's attachments in bytes.
+func (a *AttachmentsRepository) GetUsedSize(ctx context.Context, userId int) (int64, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		used  int64
+		query = fmt.Sprintf("SELECT COALESCE(SUM(size), 0) FROM %s WHERE user_id = $1", collectionAttachments)
+	)
+
+	if err = tx.QueryRowContext(ctx, query, userId).Scan(&used); err != nil {
+		return 0, err
+	}
+
+	return used, tx.Commit()
+}
+
+func (a *AttachmentsRepository) GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.Attachment, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, user_id, filename, content_type, size, storage_key, created_at FROM %s "+
+		"WHERE task_id = $1 AND user_id = $2 ORDER BY created_at, id", collectionAttachments)
+
+	rows, err := tx.QueryContext(ctx, query, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attachments []entity.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, tx.Commit()
+}
+
+func (a *AttachmentsRepository) DeleteByID(ctx context.Context, id, taskId, userId int) error {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND task_id = $2 AND user_id = $3", collectionAttachments)
+
+	_, err = tx.ExecContext(ctx, query, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteOrphaned deletes the attachments left by the purged tasks and returns their storage keys.
+func (a *AttachmentsRepository) DeleteOrphaned(ctx context.Context) ([]string, error) {
+	tx, err := a.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE task_id IS NULL RETURNING storage_key", collectionAttachments)
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = closeRows(rows); err != nil {
+		return nil, err
+	}
+
+	return keys, tx.Commit()
+}
+
+func scanAttachment(row rowScanner) (entity.Attachment, error) {
+	var attachment entity.Attachment
+
+	err := row.Scan(&attachment.ID, &attachment.TaskID, &attachment.UserID, &attachment.Filename, &attachment.ContentType,
+		&attachment.Size, &attachment.StorageKey, &attachment.CreatedAt)
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+var testAttachmentRows = []string{"id", "task_id", "user_id", "filename", "content_type", "size", "storage_key", "created_at"}
+
+func TestAttachmentsRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachments(db)
+
+	var (
+		testAttachment = entity.Attachment{
+			TaskID:      1,
+			UserID:      1,
+			Filename:    "report.pdf",
+			ContentType: "application/pdf",
+			Size:        1024,
+			StorageKey:  "1/1/key",
+		}
+		expectedQuery = "INSERT INTO attachments (task_id, user_id, filename, content_type, size, storage_key) " +
+			"SELECT $1, $2, $3, $4, $5, $6 WHERE (SELECT COALESCE(SUM(size), 0) FROM attachments WHERE user_id = $2) + $5 <= $7 RETURNING id"
+	)
+
+	type args struct {
+		attachment entity.Attachment
+		quota      int64
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       error
+	}{
+		{
+			name: "OK",
+			args: args{
+				attachment: testAttachment,
+				quota:      4096,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.attachment.TaskID, args.attachment.UserID, args.attachment.Filename, args.attachment.ContentType,
+						args.attachment.Size, args.attachment.StorageKey, args.quota).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "ERROR quota exceeded",
+			args: args{
+				attachment: testAttachment,
+				quota:      512,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.attachment.TaskID, args.attachment.UserID, args.attachment.Filename, args.attachment.ContentType,
+						args.attachment.Size, args.attachment.StorageKey, args.quota).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.attachment, tt.args.quota)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestAttachmentsRepository_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachments(db)
+
+	var (
+		testCreatedAt = time.Now().Round(time.Second)
+		expectedQuery = "SELECT id, task_id, user_id, filename, content_type, size, storage_key, created_at FROM attachments " +
+			"WHERE task_id = $1 AND user_id = $2 ORDER BY created_at, id"
+	)
+
+	type args struct {
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.Attachment
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testAttachmentRows).
+					AddRow(1, args.taskId, args.userId, "report.pdf", "application/pdf", 1024, "1/1/a", testCreatedAt).
+					AddRow(2, args.taskId, args.userId, "photo.png", "image/png", 2048, "1/1/b", testCreatedAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.Attachment{
+				{ID: 1, TaskID: 1, UserID: 1, Filename: "report.pdf", ContentType: "application/pdf", Size: 1024, StorageKey: "1/1/a", CreatedAt: testCreatedAt},
+				{ID: 2, TaskID: 1, UserID: 1, Filename: "photo.png", ContentType: "image/png", Size: 2048, StorageKey: "1/1/b", CreatedAt: testCreatedAt},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByTaskID(context.Background(), tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAttachmentsRepository_GetUsedSize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachments(db)
+
+	expectedQuery := "SELECT COALESCE(SUM(size), 0) FROM attachments WHERE user_id = $1"
+
+	type args struct {
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          int64
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(3072))
+
+				mock.ExpectCommit()
+			},
+			want: 3072,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetUsedSize(context.Background(), tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAttachmentsRepository_DeleteOrphaned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewAttachments(db)
+
+	expectedQuery := "DELETE FROM attachments WHERE task_id IS NULL RETURNING storage_key"
+
+	tests := []struct {
+		name          string
+		mockBehaviour func()
+		want          []string
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("1/1/a").AddRow("1/2/b"))
+
+				mock.ExpectCommit()
+			},
+			want: []string{"1/1/a", "1/2/b"},
+		},
+		{
+			name: "ERROR",
+			mockBehaviour: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+			got, err := repo.DeleteOrphaned(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type ChecklistsRepository struct {
+	db *sql.DB
+}
+
+func NewChecklists(db *sql.DB) *ChecklistsRepository {
+	return &ChecklistsRepository{db: db}
+}
+
+// Create appends the item to the end of the task checklist.
+func (c *ChecklistsRepository) Create(ctx context.Context, item entity.ChecklistItem) (int, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %[1]s (task_id, text, checked, position) "+
+			"SELECT $1, $2, $3, COALESCE(MAX(position) + 1, 0) FROM %[1]s WHERE task_id = $1 RETURNING id",
+			collectionChecklistItems)
+	)
+
+	err = tx.QueryRowContext(ctx, query, item.TaskID, item.Text, item.Checked).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (c *ChecklistsRepository) GetByID(ctx context.Context, id, taskId int) (entity.ChecklistItem, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.ChecklistItem{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, text, checked, position FROM %s WHERE id = $1 AND task_id = $2",
+		collectionChecklistItems)
+
+	item, err := scanChecklistItem(tx.QueryRowContext(ctx, query, id, taskId))
+	if err != nil {
+		return entity.ChecklistItem{}, err
+	}
+
+	return item, tx.Commit()
+}
+
+func (c *ChecklistsRepository) GetByTaskID(ctx context.Context, taskId int) ([]entity.ChecklistItem, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, text, checked, position FROM %s WHERE task_id = $1 ORDER BY position, id",
+		collectionChecklistItems)
+
+	rows, err := tx.QueryContext(ctx, query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []entity.ChecklistItem
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, tx.Commit()
+}
+
+// Update updates the item, when the position changes the items between the old and the new positions are shifted,
+// a position beyond the end of the checklist moves the item to the end.
+func (c *ChecklistsRepository) Update(ctx context.Context, id, taskId int, update entity.ChecklistItemUpdate) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		values = make([]string, 0)
+		args   = make([]any, 0)
+		argId  = 1
+	)
+
+	if update.Text != nil {
+		values = append(values, fmt.Sprintf("text = $%d", argId))
+		args = append(args, *update.Text)
+		argId++
+	}
+
+	if update.Checked != nil {
+		values = append(values, fmt.Sprintf("checked = $%d", argId))
+		args = append(args, *update.Checked)
+		argId++
+	}
+
+	if update.Position != nil {
+		position, err := moveChecklistItem(ctx, tx, id, taskId, *update.Position)
+		if err != nil {
+			return err
+		}
+
+		values = append(values, fmt.Sprintf("position = $%d", argId))
+		args = append(args, position)
+		argId++
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND task_id = $%d",
+		collectionChecklistItems, strings.Join(values, ", "), argId, argId+1)
+	args = append(args, id, taskId)
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteByID deletes the item and closes the gap in the positions of the following items.
+func (c *ChecklistsRepository) DeleteByID(ctx context.Context, id, taskId int) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		position int
+		query    = fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND task_id = $2 RETURNING position", collectionChecklistItems)
+	)
+
+	err = tx.QueryRowContext(ctx, query, id, taskId).Scan(&position)
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET position = position - 1 WHERE task_id = $1 AND position > $2", collectionChecklistItems)
+
+	_, err = tx.ExecContext(ctx, query, taskId, position)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// moveChecklistItem makes room for the item at the new position and returns the position clamped to the checklist size.
+func moveChecklistItem(ctx context.Context, tx *sql.Tx, id, taskId, position int) (int, error) {
+	var (
+		current, last int
+		query         = fmt.Sprintf("SELECT position, (SELECT MAX(position) FROM %[1]s WHERE task_id = $2) FROM %[1]s "+
+			"WHERE id = $1 AND task_id = $2", collectionChecklistItems)
+	)
+
+	err := tx.QueryRowContext(ctx, query, id, taskId).Scan(&current, &last)
+	if err != nil {
+		return 0, err
+	}
+
+	position = min(position, last)
+
+	switch {
+	case position < current:
+		query = fmt.Sprintf("UPDATE %s SET position = position + 1 WHERE task_id = $1 AND position >= $2 AND position < $3",
+			collectionChecklistItems)
+	case position > current:
+		query = fmt.Sprintf("UPDATE %s SET position = position - 1 WHERE task_id = $1 AND position > $3 AND position <= $2",
+			collectionChecklistItems)
+	default:
+		return position, nil
+	}
+
+	_, err = tx.ExecContext(ctx, query, taskId, position, current)
+	if err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+func scanChecklistItem(row rowScanner) (entity.ChecklistItem, error) {
+	var item entity.ChecklistItem
+
+	err := row.Scan(&item.ID, &item.TaskID, &item.Text, &item.Checked, &item.Position)
+	if err != nil {
+		return entity.ChecklistItem{}, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func TestChecklistsRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewChecklists(db)
+
+	expectedQuery := "INSERT INTO checklist_items (task_id, text, checked, position) " +
+		"SELECT $1, $2, $3, COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE task_id = $1 RETURNING id"
+
+	type args struct {
+		item entity.ChecklistItem
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				item: entity.ChecklistItem{TaskID: 1, Text: "passport"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.item.TaskID, args.item.Text, args.item.Checked).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				item: entity.ChecklistItem{TaskID: 2, Text: "passport"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.item.TaskID, args.item.Text, args.item.Checked).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.item)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestChecklistsRepository_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewChecklists(db)
+
+	expectedQuery := "SELECT id, task_id, text, checked, position FROM checklist_items WHERE task_id = $1 ORDER BY position, id"
+
+	type args struct {
+		taskId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.ChecklistItem
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "task_id", "text", "checked", "position"}).
+					AddRow(1, args.taskId, "passport", true, 0).
+					AddRow(2, args.taskId, "charger", false, 1)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.ChecklistItem{
+				{ID: 1, TaskID: 1, Text: "passport", Checked: true, Position: 0},
+				{ID: 2, TaskID: 1, Text: "charger", Checked: false, Position: 1},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByTaskID(context.Background(), tt.args.taskId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChecklistsRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewChecklists(db)
+
+	var (
+		testChecked  = true
+		testFirst    = 0
+		testBeyond   = 10
+		positionsSQL = "SELECT position, (SELECT MAX(position) FROM checklist_items WHERE task_id = $2) FROM checklist_items " +
+			"WHERE id = $1 AND task_id = $2"
+	)
+
+	type args struct {
+		id     int
+		taskId int
+		update entity.ChecklistItemUpdate
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK checked",
+			args: args{
+				id:     2,
+				taskId: 1,
+				update: entity.ChecklistItemUpdate{Checked: &testChecked},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE checklist_items SET checked = $1 WHERE id = $2 AND task_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testChecked, args.id, args.taskId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK moved up",
+			args: args{
+				id:     2,
+				taskId: 1,
+				update: entity.ChecklistItemUpdate{Position: &testFirst},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(positionsSQL)).
+					WithArgs(args.id, args.taskId).
+					WillReturnRows(sqlmock.NewRows([]string{"position", "max"}).AddRow(2, 3))
+
+				expectedExec := "UPDATE checklist_items SET position = position + 1 WHERE task_id = $1 AND position >= $2 AND position < $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, testFirst, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				expectedExec = "UPDATE checklist_items SET position = $1 WHERE id = $2 AND task_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testFirst, args.id, args.taskId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK moved beyond the end",
+			args: args{
+				id:     2,
+				taskId: 1,
+				update: entity.ChecklistItemUpdate{Position: &testBeyond},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(positionsSQL)).
+					WithArgs(args.id, args.taskId).
+					WillReturnRows(sqlmock.NewRows([]string{"position", "max"}).AddRow(1, 3))
+
+				expectedExec := "UPDATE checklist_items SET position = position - 1 WHERE task_id = $1 AND position > $3 AND position <= $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, 3, 1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				expectedExec = "UPDATE checklist_items SET position = $1 WHERE id = $2 AND task_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(3, args.id, args.taskId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     3,
+				taskId: 1,
+				update: entity.ChecklistItemUpdate{Checked: &testChecked},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE checklist_items SET checked = $1 WHERE id = $2 AND task_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testChecked, args.id, args.taskId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Update(context.Background(), tt.args.id, tt.args.taskId, tt.args.update)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChecklistsRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewChecklists(db)
+
+	expectedQuery := "DELETE FROM checklist_items WHERE id = $1 AND task_id = $2 RETURNING position"
+
+	type args struct {
+		id     int
+		taskId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     2,
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.taskId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(1))
+
+				expectedExec := "UPDATE checklist_items SET position = position - 1 WHERE task_id = $1 AND position > $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, 1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     3,
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.taskId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.taskId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
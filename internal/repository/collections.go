@@ -1,6 +1,18 @@
 package repository
 
 const (
-	collectionUsers  = "users"
-	collectionAgenda = "agenda"
+	collectionUsers            = "users"
+	collectionAgenda           = "agenda"
+	collectionTags             = "tags"
+	collectionAgendaTags       = "agenda_tags"
+	collectionProjects         = "projects"
+	collectionReminders        = "reminders"
+	collectionStatuses         = "statuses"
+	collectionTaskDependencies = "task_dependencies"
+	collectionTaskHistory      = "task_history"
+	collectionComments         = "comments"
+	collectionAttachments      = "attachments"
+	collectionChecklistItems   = "checklist_items"
+	collectionTimeEntries      = "time_entries"
+	collectionTemplates        = "templates"
 )
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type CommentsRepository struct {
+	db *sql.DB
+}
+
+func NewComments(db *sql.DB) *CommentsRepository {
+	return &CommentsRepository{db: db}
+}
+
+func (c *CommentsRepository) Create(ctx context.Context, comment entity.Comment) (int, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (task_id, user_id, body) VALUES ($1, $2, $3) RETURNING id", collectionComments)
+	)
+
+	err = tx.QueryRowContext(ctx, query, comment.TaskID, comment.UserID, comment.Body).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (c *CommentsRepository) GetByID(ctx context.Context, id, taskId int) (entity.Comment, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Comment{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, user_id, body, created_at, updated_at FROM %s WHERE id = $1 AND task_id = $2",
+		collectionComments)
+
+	comment, err := scanComment(tx.QueryRowContext(ctx, query, id, taskId))
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	return comment, tx.Commit()
+}
+
+func (c *CommentsRepository) GetByTaskID(ctx context.Context, taskId int) ([]entity.Comment, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, user_id, body, created_at, updated_at FROM %s WHERE task_id = $1 ORDER BY created_at, id",
+		collectionComments)
+
+	rows, err := tx.QueryContext(ctx, query, taskId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var comments []entity.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, tx.Commit()
+}
+
+func (c *CommentsRepository) Update(ctx context.Context, id, taskId, userId int, body string) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET body = $1, updated_at = NOW() WHERE id = $2 AND task_id = $3 AND user_id = $4",
+		collectionComments)
+
+	_, err = tx.ExecContext(ctx, query, body, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *CommentsRepository) DeleteByID(ctx context.Context, id, taskId, userId int) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND task_id = $2 AND user_id = $3", collectionComments)
+
+	_, err = tx.ExecContext(ctx, query, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanComment(row rowScanner) (entity.Comment, error) {
+	var comment entity.Comment
+
+	err := row.Scan(&comment.ID, &comment.TaskID, &comment.UserID, &comment.Body, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	return comment, nil
+}
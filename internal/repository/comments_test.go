@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+var testCommentRows = []string{"id", "task_id", "user_id", "body", "created_at", "updated_at"}
+
+func TestCommentsRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewComments(db)
+
+	expectedQuery := "INSERT INTO comments (task_id, user_id, body) VALUES ($1, $2, $3) RETURNING id"
+
+	type args struct {
+		comment entity.Comment
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				comment: entity.Comment{TaskID: 1, UserID: 1, Body: "Waiting for the review"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.comment.TaskID, args.comment.UserID, args.comment.Body).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				comment: entity.Comment{TaskID: 2, UserID: 1, Body: "Waiting for the review"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.comment.TaskID, args.comment.UserID, args.comment.Body).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.comment)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestCommentsRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewComments(db)
+
+	var (
+		testCreatedAt = time.Now().Round(time.Second)
+		expectedQuery = "SELECT id, task_id, user_id, body, created_at, updated_at FROM comments WHERE id = $1 AND task_id = $2"
+	)
+
+	type args struct {
+		id     int
+		taskId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          entity.Comment
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testCommentRows).
+					AddRow(args.id, args.taskId, 1, "Waiting for the review", testCreatedAt, nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.taskId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: entity.Comment{ID: 1, TaskID: 1, UserID: 1, Body: "Waiting for the review", CreatedAt: testCreatedAt},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.taskId).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByID(context.Background(), tt.args.id, tt.args.taskId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommentsRepository_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewComments(db)
+
+	var (
+		testCreatedAt = time.Now().Round(time.Second)
+		testUpdatedAt = testCreatedAt.Add(time.Hour)
+		expectedQuery = "SELECT id, task_id, user_id, body, created_at, updated_at FROM comments WHERE task_id = $1 ORDER BY created_at, id"
+	)
+
+	type args struct {
+		taskId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.Comment
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testCommentRows).
+					AddRow(1, args.taskId, 1, "Waiting for the review", testCreatedAt, nil).
+					AddRow(2, args.taskId, 1, "Reviewed", testCreatedAt, testUpdatedAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.Comment{
+				{ID: 1, TaskID: 1, UserID: 1, Body: "Waiting for the review", CreatedAt: testCreatedAt},
+				{ID: 2, TaskID: 1, UserID: 1, Body: "Reviewed", CreatedAt: testCreatedAt, UpdatedAt: &testUpdatedAt},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByTaskID(context.Background(), tt.args.taskId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommentsRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewComments(db)
+
+	expectedExec := "UPDATE comments SET body = $1, updated_at = NOW() WHERE id = $2 AND task_id = $3 AND user_id = $4"
+
+	type args struct {
+		id     int
+		taskId int
+		userId int
+		body   string
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				taskId: 1,
+				userId: 1,
+				body:   "Reviewed",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.body, args.id, args.taskId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				taskId: 1,
+				userId: 1,
+				body:   "Reviewed",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.body, args.id, args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Update(context.Background(), tt.args.id, tt.args.taskId, tt.args.userId, tt.args.body)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCommentsRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewComments(db)
+
+	expectedExec := "DELETE FROM comments WHERE id = $1 AND task_id = $2 AND user_id = $3"
+
+	type args struct {
+		id     int
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.taskId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
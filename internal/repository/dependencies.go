@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type DependenciesRepository struct {
+	db *sql.DB
+}
+
+func NewDependencies(db *sql.DB) *DependenciesRepository {
+	return &DependenciesRepository{db: db}
+}
+
+// Create makes the task blocked by the blocker, it returns false (and creates nothing)
+// if the blocker already depends on the task directly or transitively, so the dependency would create a cycle.
+func (d *DependenciesRepository) Create(ctx context.Context, taskId, blockerId int) (bool, error) {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("WITH RECURSIVE blockers AS (SELECT blocker_id FROM %[1]s WHERE task_id = $2 "+
+		"UNION SELECT %[1]s.blocker_id FROM %[1]s JOIN blockers ON %[1]s.task_id = blockers.blocker_id) "+
+		"INSERT INTO %[1]s (task_id, blocker_id) SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM blockers WHERE blocker_id = $1)",
+		collectionTaskDependencies)
+
+	result, err := tx.ExecContext(ctx, query, taskId, blockerId)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return created != 0, tx.Commit()
+}
+
+func (d *DependenciesRepository) GetBlockers(ctx context.Context, taskId, userId int) ([]entity.Task, error) {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $2 AND deleted_at IS NULL AND id IN (SELECT blocker_id FROM %s WHERE task_id = $1) "+
+		"ORDER BY priority DESC, date, id", taskColumns, collectionAgenda, collectionTaskDependencies)
+
+	rows, err := tx.QueryContext(ctx, query, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+func (d *DependenciesRepository) Delete(ctx context.Context, taskId, blockerId, userId int) error {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE task_id = $1 AND blocker_id = $2 AND task_id IN (SELECT id FROM %s WHERE user_id = $3)",
+		collectionTaskDependencies, collectionAgenda)
+
+	_, err = tx.ExecContext(ctx, query, taskId, blockerId, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func TestDependenciesRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewDependencies(db)
+
+	expectedExec := "WITH RECURSIVE blockers AS (SELECT blocker_id FROM task_dependencies WHERE task_id = $2 " +
+		"UNION SELECT task_dependencies.blocker_id FROM task_dependencies JOIN blockers ON task_dependencies.task_id = blockers.blocker_id) " +
+		"INSERT INTO task_dependencies (task_id, blocker_id) SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM blockers WHERE blocker_id = $1)"
+
+	type args struct {
+		taskId    int
+		blockerId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantCreated   bool
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId:    1,
+				blockerId: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, args.blockerId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantCreated: true,
+		},
+		{
+			name: "OK cycle",
+			args: args{
+				taskId:    2,
+				blockerId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, args.blockerId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectCommit()
+			},
+			wantCreated: false,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId:    1,
+				blockerId: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, args.blockerId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			created, err := repo.Create(context.Background(), tt.args.taskId, tt.args.blockerId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCreated, created)
+			}
+		})
+	}
+}
+
+func TestDependenciesRepository_GetBlockers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewDependencies(db)
+
+	var (
+		testDate        = time.Now().Round(time.Second)
+		testCompletedAt = testDate.Add(-time.Hour)
+	)
+
+	type args struct {
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.Task
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $2 AND deleted_at IS NULL AND id IN " +
+					"(SELECT blocker_id FROM task_dependencies WHERE task_id = $1) ORDER BY priority DESC, date, id"
+				rows := sqlmock.NewRows(testTaskRows).
+					AddRow(2, "Blocker 1", "", testDate, "not done", "high", nil, nil, "", nil, nil, 0, 0, "{}", 0).
+					AddRow(3, "Blocker 2", "", testDate, "done", "none", nil, nil, "", testCompletedAt, nil, 0, 0, "{}", 0)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.Task{
+				{ID: 2, Title: "Blocker 1", Date: &testDate, Status: "not done", Priority: "high", Tags: []string{}},
+				{ID: 3, Title: "Blocker 2", Date: &testDate, Status: "done", Priority: "none", CompletedAt: &testCompletedAt, Tags: []string{}},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT " + testTaskColumns + " FROM agenda WHERE user_id = $2 AND deleted_at IS NULL AND id IN " +
+					"(SELECT blocker_id FROM task_dependencies WHERE task_id = $1) ORDER BY priority DESC, date, id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetBlockers(context.Background(), tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDependenciesRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewDependencies(db)
+
+	expectedExec := "DELETE FROM task_dependencies WHERE task_id = $1 AND blocker_id = $2 AND task_id IN (SELECT id FROM agenda WHERE user_id = $3)"
+
+	type args struct {
+		taskId    int
+		blockerId int
+		userId    int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId:    1,
+				blockerId: 2,
+				userId:    1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, args.blockerId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId:    1,
+				blockerId: 2,
+				userId:    1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.taskId, args.blockerId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Delete(context.Background(), tt.args.taskId, tt.args.blockerId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/pkg/filter"
+)
+
+// filterFields are the fields of the filter query language and the operators they support.
+var filterFields = map[string][]filter.Op{
+	"status":   {filter.Equal, filter.NotEqual},
+	"priority": {filter.Equal, filter.NotEqual, filter.Less, filter.LessOrEqual, filter.Greater, filter.GreaterOrEqual},
+	"due":      {filter.Equal, filter.NotEqual, filter.Less, filter.LessOrEqual, filter.Greater, filter.GreaterOrEqual},
+	"tag":      {filter.Equal, filter.NotEqual},
+	"project":  {filter.Equal, filter.NotEqual},
+	"title":    {filter.Equal},
+	"is":       {filter.Equal},
+}
+
+// filterCompiler compiles the filter query into the condition on the agenda, the values are passed
+// only as the arguments appended to args ($1 is expected to be the user id). The dates of the query
+// are the calendar days in the location.
+type filterCompiler struct {
+	args     []any
+	location *time.Location
+}
+
+func compileFilter(query string, location *time.Location, args []any) (string, []any, error) {
+	expr, err := filter.Parse(query)
+	if err != nil || expr == nil {
+		return "", args, err
+	}
+
+	if location == nil {
+		location = time.UTC
+	}
+
+	c := &filterCompiler{args: args, location: location}
+
+	condition, err := c.compile(expr)
+	if err != nil {
+		return "", args, err
+	}
+
+	return condition, c.args, nil
+}
+
+func (c *filterCompiler) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *filterCompiler) compile(expr filter.Expr) (string, error) {
+	switch e := expr.(type) {
+	case *filter.And:
+		return c.compileBinary(e.Left, e.Right, "AND")
+	case *filter.Or:
+		return c.compileBinary(e.Left, e.Right, "OR")
+	case *filter.Not:
+		condition, err := c.compile(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		// NULL of a condition on an empty column means the task does not match, so it matches the negation
+		return fmt.Sprintf("(%s) IS NOT TRUE", condition), nil
+	case *filter.Text:
+		return c.containsText(e.Value, "title", "COALESCE(description, '')"), nil
+	case *filter.Condition:
+		return c.compileCondition(e)
+	default:
+		return "", filter.Errorf(expr.Pos(), "", "unsupported expression")
+	}
+}
+
+func (c *filterCompiler) compileBinary(left, right filter.Expr, operator string) (string, error) {
+	l, err := c.compile(left)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := c.compile(right)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("(%s %s %s)", l, operator, r), nil
+}
+
+func (c *filterCompiler) compileCondition(cond *filter.Condition) (string, error) {
+	ops, ok := filterFields[cond.Field]
+	if !ok {
+		return "", filter.Errorf(cond.Position, cond.Field, "unknown field %q", cond.Field)
+	}
+
+	if !slices.Contains(ops, cond.Op) {
+		return "", filter.Errorf(cond.OpPos, string(cond.Op), "operator %q is not supported by the field %q", cond.Op, cond.Field)
+	}
+
+	switch cond.Field {
+	case "status":
+		return c.compare("status", cond.Op, cond.Value), nil
+	case "priority":
+		if !entity.IsValidPriority(cond.Value) {
+			return "", filter.Errorf(cond.ValuePos, cond.Value, "invalid priority %q (none, low, medium, high, urgent)", cond.Value)
+		}
+		return c.compare("priority", cond.Op, cond.Value), nil
+	case "due":
+		return c.compileDue(cond)
+	case "tag":
+		return c.negate(cond.Op, fmt.Sprintf("id IN (SELECT task_id FROM %s WHERE tag_id IN (SELECT id FROM %s WHERE user_id = $1 AND name = %s))",
+			collectionAgendaTags, collectionTags, c.arg(cond.Value))), nil
+	case "project":
+		if strings.EqualFold(cond.Value, "none") {
+			return c.negate(cond.Op, "project_id IS NULL"), nil
+		}
+		return c.negate(cond.Op, fmt.Sprintf("project_id IN (SELECT id FROM %s WHERE user_id = $1 AND name = %s)",
+			collectionProjects, c.arg(cond.Value))), nil
+	case "title":
+		return c.containsText(cond.Value, "title"), nil
+	default: // is
+		switch strings.ToLower(cond.Value) {
+		case "completed":
+			return "completed_at IS NOT NULL", nil
+		case "open":
+			return "completed_at IS NULL", nil
+		case "recurring":
+			return "recurrence <> ''", nil
+		default:
+			return "", filter.Errorf(cond.ValuePos, cond.Value, "invalid value %q of the field \"is\" (completed, open, recurring)", cond.Value)
+		}
+	}
+}
+
+// compileDue compares the date with the calendar day in the user's location, the day lasts from its start
+// to the start of the next one (not always 24 hours).
+func (c *filterCompiler) compileDue(cond *filter.Condition) (string, error) {
+	start, err := time.ParseInLocation(time.DateOnly, cond.Value, c.location)
+	if err != nil {
+		return "", filter.Errorf(cond.ValuePos, cond.Value, "invalid date %q (should be like 2006-01-02)", cond.Value)
+	}
+	end := start.AddDate(0, 0, 1)
+
+	switch cond.Op {
+	case filter.Less:
+		return "date < " + c.arg(start), nil
+	case filter.LessOrEqual:
+		return "date < " + c.arg(end), nil
+	case filter.Greater:
+		return "date >= " + c.arg(end), nil
+	case filter.GreaterOrEqual:
+		return "date >= " + c.arg(start), nil
+	default:
+		return c.negate(cond.Op, fmt.Sprintf("(date >= %s AND date < %s)", c.arg(start), c.arg(end))), nil
+	}
+}
+
+func (c *filterCompiler) compare(column string, op filter.Op, value string) string {
+	if op == filter.NotEqual {
+		return fmt.Sprintf("%s <> %s", column, c.arg(value))
+	}
+	return fmt.Sprintf("%s %s %s", column, op, c.arg(value))
+}
+
+func (c *filterCompiler) negate(op filter.Op, condition string) string {
+	if op == filter.NotEqual {
+		return fmt.Sprintf("(%s) IS NOT TRUE", condition)
+	}
+	return condition
+}
+
+// containsText matches the columns containing the text case-insensitively.
+func (c *filterCompiler) containsText(text string, columns ...string) string {
+	var (
+		arg        = c.arg(text)
+		conditions = make([]string, 0, len(columns))
+	)
+
+	for _, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("STRPOS(LOWER(%s), LOWER(%s)) > 0", column, arg))
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type HistoryRepository struct {
+	db *sql.DB
+}
+
+func NewHistory(db *sql.DB) *HistoryRepository {
+	return &HistoryRepository{db: db}
+}
+
+func (h *HistoryRepository) GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.HistoryEntry, error) {
+	tx, err := h.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, task_id, user_id, action, changes, changed_at FROM %s "+
+		"WHERE task_id = $1 AND user_id = $2 ORDER BY changed_at, id", collectionTaskHistory)
+
+	rows, err := tx.QueryContext(ctx, query, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []entity.HistoryEntry
+	for rows.Next() {
+		var (
+			entry   entity.HistoryEntry
+			changes []byte
+		)
+
+		err = rows.Scan(&entry.ID, &entry.TaskID, &entry.UserID, &entry.Action, &changes, &entry.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if changes != nil {
+			if err = json.Unmarshal(changes, &entry.Changes); err != nil {
+				return nil, err
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, tx.Commit()
+}
+
+// recordHistory appends the entries to the task history within the transaction of the change.
+func recordHistory(ctx context.Context, tx *sql.Tx, entries ...entity.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var (
+		values = make([]string, 0, len(entries))
+		args   = make([]any, 0, 4*len(entries))
+	)
+
+	for _, entry := range entries {
+		var changes any
+		if len(entry.Changes) != 0 {
+			data, err := json.Marshal(entry.Changes)
+			if err != nil {
+				return err
+			}
+			changes = string(data)
+		}
+
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d)", len(args)+1, len(args)+2, len(args)+3, len(args)+4))
+		args = append(args, entry.TaskID, entry.UserID, entry.Action, changes)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (task_id, user_id, action, changes) VALUES %s",
+		collectionTaskHistory, strings.Join(values, ", "))
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// historyEntries returns the same history entry for each of the tasks.
+func historyEntries(ids []int, userId int, action string) []entity.HistoryEntry {
+	entries := make([]entity.HistoryEntry, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, entity.HistoryEntry{TaskID: id, UserID: userId, Action: action})
+	}
+	return entries
+}
+
+// taskChanges returns the fields changed between the task versions,
+// for a created task (old is nil) these are all its set fields with no old values.
+func taskChanges(old *entity.Task, task entity.Task) map[string]entity.FieldChange {
+	var (
+		prev    entity.Task
+		changes = make(map[string]entity.FieldChange)
+	)
+
+	if old != nil {
+		prev = *old
+	}
+
+	change := func(field string, changed bool, oldValue, newValue any) {
+		if !changed {
+			return
+		}
+		if old == nil {
+			oldValue = nil
+		}
+		changes[field] = entity.FieldChange{Old: oldValue, New: newValue}
+	}
+
+	change("title", prev.Title != task.Title, prev.Title, task.Title)
+	change("description", prev.Description != task.Description, prev.Description, task.Description)
+	change("date", !sameTime(prev.Date, task.Date), prev.Date, task.Date)
+	change("status", prev.Status != task.Status, prev.Status, task.Status)
+	change("priority", prev.Priority != task.Priority, prev.Priority, task.Priority)
+	change("project_id", !sameID(prev.ProjectID, task.ProjectID), prev.ProjectID, task.ProjectID)
+	change("parent_id", !sameID(prev.ParentID, task.ParentID), prev.ParentID, task.ParentID)
+	change("recurrence", prev.Recurrence != task.Recurrence, prev.Recurrence, task.Recurrence)
+	change("estimate_minutes", prev.EstimateMinutes != task.EstimateMinutes, prev.EstimateMinutes, task.EstimateMinutes)
+	change("estimate_points", prev.EstimatePoints != task.EstimatePoints, prev.EstimatePoints, task.EstimatePoints)
+	change("tags", strings.Join(prev.Tags, ",") != strings.Join(task.Tags, ","), prev.Tags, task.Tags)
+
+	return changes
+}
+
+func sameID(lhs, rhs *int) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+	return *lhs == *rhs
+}
+
+func sameTime(lhs, rhs *time.Time) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+	return lhs.Equal(*rhs)
+}
+
+// queryIDs runs the query returning ids of the changed tasks, the rows are closed
+// so the transaction can be used for recording the history right away.
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, closeRows(rows)
+}
+
+func closeRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	return rows.Close()
+}
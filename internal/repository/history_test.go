@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func TestHistoryRepository_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewHistory(db)
+
+	var (
+		testChangedAt = time.Now().Round(time.Second)
+		expectedQuery = "SELECT id, task_id, user_id, action, changes, changed_at FROM task_history " +
+			"WHERE task_id = $1 AND user_id = $2 ORDER BY changed_at, id"
+	)
+
+	type args struct {
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.HistoryEntry
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "task_id", "user_id", "action", "changes", "changed_at"}).
+					AddRow(1, args.taskId, args.userId, entity.HistoryStatusChanged, []byte(`{"status":{"old":"not done","new":"done"}}`), testChangedAt).
+					AddRow(2, args.taskId, args.userId, entity.HistoryDeleted, nil, testChangedAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.HistoryEntry{
+				{
+					ID:        1,
+					TaskID:    1,
+					UserID:    1,
+					Action:    entity.HistoryStatusChanged,
+					Changes:   map[string]entity.FieldChange{"status": {Old: "not done", New: "done"}},
+					ChangedAt: testChangedAt,
+				},
+				{ID: 2, TaskID: 1, UserID: 1, Action: entity.HistoryDeleted, ChangedAt: testChangedAt},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByTaskID(context.Background(), tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type ProjectsRepository struct {
+	db *sql.DB
+}
+
+func NewProjects(db *sql.DB) *ProjectsRepository {
+	return &ProjectsRepository{db: db}
+}
+
+func (p *ProjectsRepository) Create(ctx context.Context, project entity.Project) (int, error) {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (user_id, name, description) VALUES ($1, $2, $3) RETURNING id",
+			collectionProjects)
+	)
+
+	err = tx.QueryRowContext(ctx, query, project.UserID, project.Name, project.Description).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (p *ProjectsRepository) GetByID(ctx context.Context, id, userId int) (entity.Project, error) {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Project{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		project entity.Project
+		query   = fmt.Sprintf("SELECT id, name, COALESCE(description, '') FROM %s WHERE id = $1 AND user_id = $2",
+			collectionProjects)
+	)
+
+	err = tx.QueryRowContext(ctx, query, id, userId).Scan(&project.ID, &project.Name, &project.Description)
+	if err != nil {
+		return entity.Project{}, err
+	}
+
+	return project, tx.Commit()
+}
+
+func (p *ProjectsRepository) GetByUserID(ctx context.Context, userId int) ([]entity.Project, error) {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		projects []entity.Project
+		query    = fmt.Sprintf("SELECT id, name, COALESCE(description, '') FROM %s WHERE user_id = $1 ORDER BY name",
+			collectionProjects)
+	)
+
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var project entity.Project
+		if err = rows.Scan(&project.ID, &project.Name, &project.Description); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return projects, tx.Commit()
+}
+
+func (p *ProjectsRepository) Update(ctx context.Context, project entity.Project) error {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET name = $1, description = $2 WHERE id = $3 AND user_id = $4",
+		collectionProjects)
+
+	_, err = tx.ExecContext(ctx, query, project.Name, project.Description, project.ID, project.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *ProjectsRepository) DeleteByID(ctx context.Context, id, userId int) error {
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var query = fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", collectionProjects)
+
+	_, err = tx.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
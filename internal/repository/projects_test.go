@@ -0,0 +1,375 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func TestProjectsRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewProjects(db)
+
+	type args struct {
+		project entity.Project
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				project: entity.Project{UserID: 1, Name: "Home", Description: "Home stuff"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO projects (user_id, name, description) VALUES ($1, $2, $3) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.project.UserID, args.project.Name, args.project.Description).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				project: entity.Project{UserID: 1, Name: "Home"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO projects (user_id, name, description) VALUES ($1, $2, $3) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.project.UserID, args.project.Name, args.project.Description).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.project)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestProjectsRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewProjects(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantProject   entity.Project
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "name", "description"}).AddRow(args.id, "Home", "Home stuff")
+
+				expectedQuery := "SELECT id, name, COALESCE(description, '') FROM projects WHERE id = $1 AND user_id = $2"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantProject: entity.Project{ID: 1, Name: "Home", Description: "Home stuff"},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT id, name, COALESCE(description, '') FROM projects WHERE id = $1 AND user_id = $2"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			project, err := repo.GetByID(context.Background(), tt.args.id, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProject, project)
+			}
+		})
+	}
+}
+
+func TestProjectsRepository_GetByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewProjects(db)
+
+	type args struct {
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantProjects  []entity.Project
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "name", "description"}).
+					AddRow(2, "Home", "").
+					AddRow(1, "Work", "Work stuff")
+
+				expectedQuery := "SELECT id, name, COALESCE(description, '') FROM projects WHERE user_id = $1 ORDER BY name"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantProjects: []entity.Project{
+				{ID: 2, Name: "Home"},
+				{ID: 1, Name: "Work", Description: "Work stuff"},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userId: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT id, name, COALESCE(description, '') FROM projects WHERE user_id = $1 ORDER BY name"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			projects, err := repo.GetByUserID(context.Background(), tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantProjects, projects)
+			}
+		})
+	}
+}
+
+func TestProjectsRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewProjects(db)
+
+	type args struct {
+		project entity.Project
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				project: entity.Project{ID: 1, UserID: 1, Name: "Work", Description: "Work stuff"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE projects SET name = $1, description = $2 WHERE id = $3 AND user_id = $4"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.project.Name, args.project.Description, args.project.ID, args.project.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				project: entity.Project{ID: 2, UserID: 1, Name: "Work"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE projects SET name = $1, description = $2 WHERE id = $3 AND user_id = $4"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.project.Name, args.project.Description, args.project.ID, args.project.UserID).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Update(context.Background(), tt.args.project)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProjectsRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewProjects(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "DELETE FROM projects WHERE id = $1 AND user_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "DELETE FROM projects WHERE id = $1 AND user_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
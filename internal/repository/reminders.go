@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+// reminderFireAt is the time the reminder is due, relative reminders follow the current task date.
+var reminderFireAt = fmt.Sprintf("COALESCE(%[1]s.remind_at, %[2]s.date - MAKE_INTERVAL(mins => %[1]s.offset_minutes))",
+	collectionReminders, collectionAgenda)
+
+type RemindersRepository struct {
+	db *sql.DB
+}
+
+func NewReminders(db *sql.DB) *RemindersRepository {
+	return &RemindersRepository{db: db}
+}
+
+func (r *RemindersRepository) Create(ctx context.Context, reminder entity.Reminder) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (user_id, task_id, remind_at, offset_minutes) VALUES ($1, $2, $3, $4) RETURNING id",
+			collectionReminders)
+	)
+
+	err = tx.QueryRowContext(ctx, query, reminder.UserID, reminder.TaskID, reminder.RemindAt, reminder.OffsetMinutes).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *RemindersRepository) GetByID(ctx context.Context, id, taskId, userId int) (entity.Reminder, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Reminder{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %[1]s.id, %[1]s.task_id, %[1]s.remind_at, %[1]s.offset_minutes, %[3]s AS fire_at, %[1]s.sent_at "+
+		"FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.task_id WHERE %[1]s.id = $1 AND %[1]s.task_id = $2 AND %[1]s.user_id = $3",
+		collectionReminders, collectionAgenda, reminderFireAt)
+
+	reminder, err := scanReminder(tx.QueryRowContext(ctx, query, id, taskId, userId))
+	if err != nil {
+		return entity.Reminder{}, err
+	}
+
+	return reminder, tx.Commit()
+}
+
+func (r *RemindersRepository) GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.Reminder, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %[1]s.id, %[1]s.task_id, %[1]s.remind_at, %[1]s.offset_minutes, %[3]s AS fire_at, %[1]s.sent_at "+
+		"FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.task_id WHERE %[1]s.task_id = $1 AND %[1]s.user_id = $2 ORDER BY fire_at, %[1]s.id",
+		collectionReminders, collectionAgenda, reminderFireAt)
+
+	rows, err := tx.QueryContext(ctx, query, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reminders []entity.Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reminders, tx.Commit()
+}
+
+func (r *RemindersRepository) DeleteByID(ctx context.Context, id, taskId, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND task_id = $2 AND user_id = $3", collectionReminders)
+
+	_, err = tx.ExecContext(ctx, query, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ClaimDue marks up to limit due reminders of not completed tasks as sent and returns them.
+// A reminder is claimed before it is delivered, so it is never fired twice even if the
+// process stops in the middle of delivery; concurrent dispatchers skip each other's rows.
+// Every claim is counted as an attempt, reminders with maxAttempts attempts are not claimed anymore.
+func (r *RemindersRepository) ClaimDue(ctx context.Context, limit, maxAttempts int) ([]entity.DueReminder, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %[1]s SET sent_at = NOW(), attempts = %[1]s.attempts + 1 FROM %[2]s, %[3]s "+
+		"WHERE %[1]s.id IN (SELECT %[1]s.id FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.task_id "+
+		"WHERE %[1]s.sent_at IS NULL AND %[2]s.completed_at IS NULL AND %[2]s.deleted_at IS NULL AND %[4]s <= NOW() "+
+		"AND %[1]s.attempts < $2 AND (%[1]s.next_attempt_at IS NULL OR %[1]s.next_attempt_at <= NOW()) "+
+		"ORDER BY %[1]s.id LIMIT $1 FOR UPDATE OF %[1]s SKIP LOCKED) "+
+		"AND %[2]s.id = %[1]s.task_id AND %[3]s.id = %[1]s.user_id "+
+		"RETURNING %[1]s.id, %[1]s.user_id, %[3]s.email, %[2]s.id, %[2]s.title, %[2]s.date, %[4]s",
+		collectionReminders, collectionAgenda, collectionUsers, reminderFireAt)
+
+	rows, err := tx.QueryContext(ctx, query, limit, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reminders []entity.DueReminder
+	for rows.Next() {
+		var reminder entity.DueReminder
+		err = rows.Scan(&reminder.ReminderID, &reminder.UserID, &reminder.Email, &reminder.TaskID,
+			&reminder.Title, &reminder.Date, &reminder.FireAt)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reminders, tx.Commit()
+}
+
+// Release returns the claimed reminder back to the pending ones when its delivery failed, it is retried
+// after the backoff doubled on every attempt but the first one and limited by maxBackoff.
+func (r *RemindersRepository) Release(ctx context.Context, id int, backoff, maxBackoff time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET sent_at = NULL, "+
+		"next_attempt_at = NOW() + MAKE_INTERVAL(secs => LEAST($2 * POWER(2, GREATEST(attempts - 1, 0)), $3)) WHERE id = $1",
+		collectionReminders)
+
+	_, err = tx.ExecContext(ctx, query, id, backoff.Seconds(), maxBackoff.Seconds())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanReminder(row rowScanner) (entity.Reminder, error) {
+	var reminder entity.Reminder
+
+	err := row.Scan(&reminder.ID, &reminder.TaskID, &reminder.RemindAt, &reminder.OffsetMinutes, &reminder.FireAt, &reminder.SentAt)
+	if err != nil {
+		return entity.Reminder{}, err
+	}
+
+	return reminder, nil
+}
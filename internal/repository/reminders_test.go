@@ -0,0 +1,437 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+const testReminderFireAt = "COALESCE(reminders.remind_at, agenda.date - MAKE_INTERVAL(mins => reminders.offset_minutes))"
+
+func TestRemindersRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewReminders(db)
+
+	var (
+		testRemindAt      = time.Now().Round(time.Second)
+		testOffsetMinutes = 30
+	)
+
+	type args struct {
+		reminder entity.Reminder
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       bool
+	}{
+		{
+			name: "OK absolute",
+			args: args{
+				reminder: entity.Reminder{UserID: 1, TaskID: 1, RemindAt: &testRemindAt},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO reminders (user_id, task_id, remind_at, offset_minutes) VALUES ($1, $2, $3, $4) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.reminder.UserID, args.reminder.TaskID, args.reminder.RemindAt, args.reminder.OffsetMinutes).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "OK relative",
+			args: args{
+				reminder: entity.Reminder{UserID: 1, TaskID: 1, OffsetMinutes: &testOffsetMinutes},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO reminders (user_id, task_id, remind_at, offset_minutes) VALUES ($1, $2, $3, $4) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.reminder.UserID, args.reminder.TaskID, args.reminder.RemindAt, args.reminder.OffsetMinutes).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				mock.ExpectCommit()
+			},
+			wantID: 2,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				reminder: entity.Reminder{UserID: 1, TaskID: 2, OffsetMinutes: &testOffsetMinutes},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO reminders (user_id, task_id, remind_at, offset_minutes) VALUES ($1, $2, $3, $4) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.reminder.UserID, args.reminder.TaskID, args.reminder.RemindAt, args.reminder.OffsetMinutes).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.reminder)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestRemindersRepository_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewReminders(db)
+
+	var (
+		testFireAt        = time.Now().Round(time.Second)
+		testOffsetMinutes = 15
+	)
+
+	type args struct {
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantReminders []entity.Reminder
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "task_id", "remind_at", "offset_minutes", "fire_at", "sent_at"}).
+					AddRow(1, args.taskId, nil, testOffsetMinutes, testFireAt, nil)
+
+				expectedQuery := "SELECT reminders.id, reminders.task_id, reminders.remind_at, reminders.offset_minutes, " +
+					testReminderFireAt + " AS fire_at, reminders.sent_at FROM reminders JOIN agenda ON agenda.id = reminders.task_id " +
+					"WHERE reminders.task_id = $1 AND reminders.user_id = $2 ORDER BY fire_at, reminders.id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.taskId, args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantReminders: []entity.Reminder{
+				{ID: 1, TaskID: 1, OffsetMinutes: &testOffsetMinutes, FireAt: &testFireAt},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT reminders.id, reminders.task_id, reminders.remind_at, reminders.offset_minutes, " +
+					testReminderFireAt + " AS fire_at, reminders.sent_at FROM reminders JOIN agenda ON agenda.id = reminders.task_id " +
+					"WHERE reminders.task_id = $1 AND reminders.user_id = $2 ORDER BY fire_at, reminders.id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			reminders, err := repo.GetByTaskID(context.Background(), tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReminders, reminders)
+			}
+		})
+	}
+}
+
+func TestRemindersRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewReminders(db)
+
+	type args struct {
+		id     int
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "DELETE FROM reminders WHERE id = $1 AND task_id = $2 AND user_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.taskId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "DELETE FROM reminders WHERE id = $1 AND task_id = $2 AND user_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemindersRepository_ClaimDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewReminders(db)
+
+	var (
+		testDate   = time.Now().Round(time.Second)
+		testFireAt = testDate.Add(-time.Hour)
+	)
+
+	const expectedQuery = "UPDATE reminders SET sent_at = NOW(), attempts = reminders.attempts + 1 FROM agenda, users " +
+		"WHERE reminders.id IN (SELECT reminders.id FROM reminders JOIN agenda ON agenda.id = reminders.task_id " +
+		"WHERE reminders.sent_at IS NULL AND agenda.completed_at IS NULL AND agenda.deleted_at IS NULL AND " + testReminderFireAt + " <= NOW() " +
+		"AND reminders.attempts < $2 AND (reminders.next_attempt_at IS NULL OR reminders.next_attempt_at <= NOW()) " +
+		"ORDER BY reminders.id LIMIT $1 FOR UPDATE OF reminders SKIP LOCKED) " +
+		"AND agenda.id = reminders.task_id AND users.id = reminders.user_id " +
+		"RETURNING reminders.id, reminders.user_id, users.email, agenda.id, agenda.title, agenda.date, " + testReminderFireAt
+
+	type args struct {
+		limit       int
+		maxAttempts int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantReminders []entity.DueReminder
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				limit:       10,
+				maxAttempts: 5,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "email", "task_id", "title", "date", "fire_at"}).
+					AddRow(1, 1, "email@go.dev", 3, "Pay bills", testDate, testFireAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.limit, args.maxAttempts).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantReminders: []entity.DueReminder{
+				{ReminderID: 1, UserID: 1, Email: "email@go.dev", TaskID: 3, Title: "Pay bills", Date: &testDate, FireAt: testFireAt},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				limit:       10,
+				maxAttempts: 5,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.limit, args.maxAttempts).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			reminders, err := repo.ClaimDue(context.Background(), tt.args.limit, tt.args.maxAttempts)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantReminders, reminders)
+			}
+		})
+	}
+}
+
+func TestRemindersRepository_Release(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewReminders(db)
+
+	type args struct {
+		id         int
+		backoff    time.Duration
+		maxBackoff time.Duration
+	}
+
+	const expectedExec = "UPDATE reminders SET sent_at = NULL, " +
+		"next_attempt_at = NOW() + MAKE_INTERVAL(secs => LEAST($2 * POWER(2, GREATEST(attempts - 1, 0)), $3)) WHERE id = $1"
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:         1,
+				backoff:    time.Minute,
+				maxBackoff: time.Hour,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.backoff.Seconds(), args.maxBackoff.Seconds()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:         2,
+				backoff:    time.Minute,
+				maxBackoff: time.Hour,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.backoff.Seconds(), args.maxBackoff.Seconds()).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Release(context.Background(), tt.args.id, tt.args.backoff, tt.args.maxBackoff)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -16,28 +16,153 @@ type (
 		GetByCredentials(ctx context.Context, login, password string) (entity.User, error)
 		GetByRefreshToken(ctx context.Context, refreshToken string) (entity.User, error)
 		SetSession(ctx context.Context, userId int, session entity.Session) error
+		GetTimezone(ctx context.Context, id int) (string, error)
+		SetTimezone(ctx context.Context, id int, timezone string) error
 	}
 
 	Agenda interface {
 		Create(ctx context.Context, task entity.Task) (int, error)
 		GetByID(ctx context.Context, id, userId int) (entity.Task, error)
-		GetByTitleAndUserID(ctx context.Context, title string, userId int) (entity.Task, error)
-		SetStatus(ctx context.Context, id, userId int, status string) error
-		DeleteByID(ctx context.Context, id, userId int) error
+		GetByTitleAndUserID(ctx context.Context, title string, userId int, projectId *int) (entity.Task, error)
+		SetStatus(ctx context.Context, id, userId int, status string, completed, withSubtasks bool, next *entity.Task) error
+		SetProject(ctx context.Context, id, userId int, projectId *int) error
+		Move(ctx context.Context, id, userId, targetId int, before bool) error
+		Update(ctx context.Context, id, userId int, update entity.TaskUpdate, next *entity.Task) (entity.Task, error)
+		DeleteByID(ctx context.Context, id, userId int, withSubtasks bool) error
 		DeleteByUserID(ctx context.Context, userId int) error
-		GetByUserID(ctx context.Context, userId int) ([]entity.Task, error)
-		GetByDateAndStatus(ctx context.Context, userId int, status string, date time.Time, limit, offset int) ([]entity.Task, error)
+		GetSubtasks(ctx context.Context, id, userId int) ([]entity.Task, error)
+		GetByUserID(ctx context.Context, userId int, filter entity.TaskFilter) ([]entity.Task, error)
+		List(ctx context.Context, userId int, filter entity.TaskFilter) (entity.TaskPage, error)
+		GetWorkload(ctx context.Context, userId int, from, to time.Time) ([]entity.WorkloadDay, error)
+		GetTrashedByID(ctx context.Context, id, userId int) (entity.Task, error)
+		GetTrash(ctx context.Context, userId int) ([]entity.Task, error)
+		Restore(ctx context.Context, id, userId int) error
+		Purge(ctx context.Context, before time.Time) (int64, error)
+		Search(ctx context.Context, userId int, query string, limit, offset int) ([]entity.TaskSearchResult, error)
+		GetArchived(ctx context.Context, userId int, search string, limit, offset int) ([]entity.Task, error)
+		Unarchive(ctx context.Context, id, userId int) error
+		Archive(ctx context.Context, before time.Time, limit int) (int, error)
+	}
+
+	Tags interface {
+		Create(ctx context.Context, tag entity.Tag) (int, error)
+		GetByID(ctx context.Context, id, userId int) (entity.Tag, error)
+		GetByUserID(ctx context.Context, userId int) ([]entity.Tag, error)
+		Rename(ctx context.Context, id, userId int, name string) error
+		DeleteByID(ctx context.Context, id, userId int) error
+	}
+
+	Projects interface {
+		Create(ctx context.Context, project entity.Project) (int, error)
+		GetByID(ctx context.Context, id, userId int) (entity.Project, error)
+		GetByUserID(ctx context.Context, userId int) ([]entity.Project, error)
+		Update(ctx context.Context, project entity.Project) error
+		DeleteByID(ctx context.Context, id, userId int) error
+	}
+
+	Reminders interface {
+		Create(ctx context.Context, reminder entity.Reminder) (int, error)
+		GetByID(ctx context.Context, id, taskId, userId int) (entity.Reminder, error)
+		GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.Reminder, error)
+		DeleteByID(ctx context.Context, id, taskId, userId int) error
+		ClaimDue(ctx context.Context, limit, maxAttempts int) ([]entity.DueReminder, error)
+		Release(ctx context.Context, id int, backoff, maxBackoff time.Duration) error
+	}
+
+	Statuses interface {
+		Create(ctx context.Context, status entity.Status) (int, error)
+		GetByID(ctx context.Context, id, userId int) (entity.Status, error)
+		GetByProjectID(ctx context.Context, userId int, projectId *int) ([]entity.Status, error)
+		GetByUserID(ctx context.Context, userId int) ([]entity.Status, error)
+		Update(ctx context.Context, status entity.Status, oldName string) error
+		DeleteByID(ctx context.Context, id, userId int) error
+		IsUsed(ctx context.Context, status entity.Status) (bool, error)
+	}
+
+	Dependencies interface {
+		Create(ctx context.Context, taskId, blockerId int) (bool, error)
+		GetBlockers(ctx context.Context, taskId, userId int) ([]entity.Task, error)
+		Delete(ctx context.Context, taskId, blockerId, userId int) error
+	}
+
+	History interface {
+		GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.HistoryEntry, error)
+	}
+
+	Comments interface {
+		Create(ctx context.Context, comment entity.Comment) (int, error)
+		GetByID(ctx context.Context, id, taskId int) (entity.Comment, error)
+		GetByTaskID(ctx context.Context, taskId int) ([]entity.Comment, error)
+		Update(ctx context.Context, id, taskId, userId int, body string) error
+		DeleteByID(ctx context.Context, id, taskId, userId int) error
+	}
+
+	Attachments interface {
+		Create(ctx context.Context, attachment entity.Attachment, quota int64) (int, error)
+		GetByID(ctx context.Context, id, taskId, userId int) (entity.Attachment, error)
+		GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.Attachment, error)
+		GetUsedSize(ctx context.Context, userId int) (int64, error)
+		DeleteByID(ctx context.Context, id, taskId, userId int) error
+		DeleteOrphaned(ctx context.Context) ([]string, error)
+	}
+
+	Checklists interface {
+		Create(ctx context.Context, item entity.ChecklistItem) (int, error)
+		GetByID(ctx context.Context, id, taskId int) (entity.ChecklistItem, error)
+		GetByTaskID(ctx context.Context, taskId int) ([]entity.ChecklistItem, error)
+		Update(ctx context.Context, id, taskId int, update entity.ChecklistItemUpdate) error
+		DeleteByID(ctx context.Context, id, taskId int) error
+	}
+
+	TimeEntries interface {
+		Create(ctx context.Context, entry entity.TimeEntry) (int, error)
+		GetByID(ctx context.Context, id, taskId, userId int) (entity.TimeEntry, error)
+		GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.TimeEntry, error)
+		Stop(ctx context.Context, userId int) (entity.TimeEntry, error)
+		Update(ctx context.Context, id, taskId, userId int, update entity.TimeEntryUpdate) error
+		DeleteByID(ctx context.Context, id, taskId, userId int) error
+		GetReport(ctx context.Context, userId int, from, to time.Time) ([]entity.TimeReportRow, error)
+	}
+
+	Templates interface {
+		Create(ctx context.Context, template entity.Template) (int, error)
+		GetByID(ctx context.Context, id, userId int) (entity.Template, error)
+		GetByUserID(ctx context.Context, userId int) ([]entity.Template, error)
+		Update(ctx context.Context, template entity.Template) error
+		DeleteByID(ctx context.Context, id, userId int) error
 	}
 )
 
 type Repositories struct {
 	Users
 	Agenda
+	Tags
+	Projects
+	Reminders
+	Statuses
+	Dependencies
+	History
+	Comments
+	Attachments
+	Checklists
+	TimeEntries
+	Templates
 }
 
 func New(db *sql.DB) *Repositories {
 	return &Repositories{
-		Users:  NewUsers(db),
-		Agenda: NewAgenda(db),
+		Users:        NewUsers(db),
+		Agenda:       NewAgenda(db),
+		Tags:         NewTags(db),
+		Projects:     NewProjects(db),
+		Reminders:    NewReminders(db),
+		Statuses:     NewStatuses(db),
+		Dependencies: NewDependencies(db),
+		History:      NewHistory(db),
+		Comments:     NewComments(db),
+		Attachments:  NewAttachments(db),
+		Checklists:   NewChecklists(db),
+		TimeEntries:  NewTimeEntries(db),
+		Templates:    NewTemplates(db),
 	}
 }
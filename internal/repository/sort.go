@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+// sortColumns are the fields the task listings can be sorted by.
+var sortColumns = map[string]sortColumn{
+	"rank":             {name: "rank", kind: textValue},
+	"id":               {name: "id", kind: integerValue},
+	"title":            {name: "title", kind: textValue},
+	"date":             {name: "date", kind: timestampValue, nullable: true},
+	"status":           {name: "status", kind: textValue},
+	"priority":         {name: "priority", kind: priorityValue},
+	"completed_at":     {name: "completed_at", kind: timestampValue, nullable: true},
+	"estimate_minutes": {name: "estimate_minutes", kind: integerValue},
+	"estimate_points":  {name: "estimate_points", kind: integerValue},
+}
+
+// kinds of the sort column values, the cursor values are checked against them.
+const (
+	textValue = iota
+	integerValue
+	timestampValue
+	priorityValue
+)
+
+// timestampLayouts are the text forms of TIMESTAMPTZ in the ISO date style.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07:00:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// defaultSort keeps the manual order of the tasks.
+const defaultSort = "rank"
+
+type sortColumn struct {
+	name     string
+	kind     int
+	nullable bool // NULLs go last in both directions
+}
+
+type sortKey struct {
+	sortColumn
+	desc bool
+}
+
+// parseSort parses the comma-separated fields, a leading "-" sorts the field in descending order.
+func parseSort(sort string) ([]sortKey, error) {
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
+
+	var (
+		keys = make([]sortKey, 0)
+		seen = make(map[string]bool)
+	)
+
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		column, ok := sortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", entity.ErrInvalidSort, field)
+		}
+
+		if seen[field] {
+			return nil, fmt.Errorf("%w: field %q is repeated", entity.ErrInvalidSort, field)
+		}
+		seen[field] = true
+
+		keys = append(keys, sortKey{sortColumn: column, desc: desc})
+	}
+
+	return keys, nil
+}
+
+// orderBy returns the ORDER BY clause, id breaks the ties so the order is the same on every page.
+func orderBy(keys []sortKey) string {
+	clauses := make([]string, 0, len(keys)+1)
+
+	for _, key := range keys {
+		clause := key.name
+		if key.desc {
+			clause += " DESC"
+		}
+		if key.nullable {
+			clause += " NULLS LAST"
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return "ORDER BY " + strings.Join(append(clauses, "id"), ", ")
+}
+
+// sortValues returns the columns of the sort key values, they are read as text and kept in the cursor.
+func sortValues(keys []sortKey) string {
+	columns := make([]string, 0, len(keys))
+	for _, key := range keys {
+		columns = append(columns, key.name+"::TEXT")
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// checkCursor checks that the cursor values fit the sort columns, so a forged cursor is reported as invalid
+// instead of failing the query.
+func checkCursor(keys []sortKey, cursor entity.TaskCursor) error {
+	for i, key := range keys {
+		value := cursor.Values[i]
+		if value == nil {
+			if !key.nullable {
+				return fmt.Errorf("%w: %s must not be null", entity.ErrInvalidCursor, key.name)
+			}
+			continue
+		}
+
+		if !isValidSortValue(key.kind, *value) {
+			return fmt.Errorf("%w: invalid %s %q", entity.ErrInvalidCursor, key.name, *value)
+		}
+	}
+
+	return nil
+}
+
+func isValidSortValue(kind int, value string) bool {
+	switch kind {
+	case integerValue:
+		_, err := strconv.ParseInt(value, 10, 32)
+		return err == nil
+	case timestampValue:
+		for _, layout := range timestampLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	case priorityValue:
+		return entity.IsValidPriority(value)
+	}
+
+	return true
+}
+
+// afterCursor returns the condition on the tasks following the cursor in the order, the values are
+// compared as text converted to the column types and appended to args.
+func afterCursor(keys []sortKey, cursor entity.TaskCursor, args []any) (string, []any) {
+	if isRowComparable(keys, cursor) {
+		placeholders := make([]string, 0, len(keys)+1)
+		for _, value := range cursor.Values {
+			args = append(args, *value)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		args = append(args, cursor.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+
+		columns := make([]string, 0, len(keys)+1)
+		for _, key := range keys {
+			columns = append(columns, key.name)
+		}
+
+		return fmt.Sprintf("(%s, id) > (%s)", strings.Join(columns, ", "), strings.Join(placeholders, ", ")), args
+	}
+
+	args = append(args, cursor.ID)
+	condition := fmt.Sprintf("id > $%d", len(args))
+
+	// built from the last key: the task follows if it is after the cursor on the key
+	// or equal to it on the key and follows it on the rest ones
+	for i := len(keys) - 1; i >= 0; i-- {
+		key, value := keys[i], cursor.Values[i]
+
+		if value == nil {
+			condition = fmt.Sprintf("(%s IS NULL AND %s)", key.name, condition)
+			continue
+		}
+
+		args = append(args, *value)
+
+		operator := ">"
+		if key.desc {
+			operator = "<"
+		}
+
+		after := fmt.Sprintf("%s %s $%d", key.name, operator, len(args))
+		if key.nullable {
+			after = fmt.Sprintf("%s OR %s IS NULL", after, key.name)
+		}
+
+		condition = fmt.Sprintf("(%s OR (%s = $%d AND %s))", after, key.name, len(args), condition)
+	}
+
+	return condition, args
+}
+
+// isRowComparable reports whether the tasks following the cursor can be found by the row comparison,
+// which is the case for the ascending keys without NULLs.
+func isRowComparable(keys []sortKey, cursor entity.TaskCursor) bool {
+	for i, key := range keys {
+		if key.desc || key.nullable || cursor.Values[i] == nil {
+			return false
+		}
+	}
+
+	return true
+}
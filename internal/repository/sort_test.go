@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func TestCheckCursor(t *testing.T) {
+	value := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		sort    string
+		values  []*string
+		wantErr bool
+	}{
+		{name: "OK rank", sort: "rank", values: []*string{value("V000000001V")}},
+		{name: "OK date in UTC", sort: "date", values: []*string{value("2026-10-18 10:00:00+00")}},
+		{name: "OK date with fraction and offset", sort: "date", values: []*string{value("2026-10-18 10:00:00.123456+05:30")}},
+		{name: "OK null date", sort: "date", values: []*string{nil}},
+		{name: "OK several fields", sort: "-priority,estimate_points,completed_at", values: []*string{value("high"), value("8"), nil}},
+		{name: "ERROR date", sort: "date", values: []*string{value("2026-10-18T10:00:00Z")}, wantErr: true},
+		{name: "ERROR completed at", sort: "completed_at", values: []*string{value("")}, wantErr: true},
+		{name: "ERROR id", sort: "id", values: []*string{value("1 OR 1=1")}, wantErr: true},
+		{name: "ERROR estimate out of range", sort: "estimate_minutes", values: []*string{value("99999999999")}, wantErr: true},
+		{name: "ERROR priority", sort: "priority", values: []*string{value("critical")}, wantErr: true},
+		{name: "ERROR null status", sort: "status", values: []*string{nil}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseSort(tt.sort)
+			if err != nil {
+				t.Fatalf("error parsing sort: %v\n", err)
+			}
+
+			err = checkCursor(keys, entity.TaskCursor{Sort: tt.sort, Values: tt.values, ID: 1})
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, entity.ErrInvalidCursor)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type StatusesRepository struct {
+	db *sql.DB
+}
+
+func NewStatuses(db *sql.DB) *StatusesRepository {
+	return &StatusesRepository{db: db}
+}
+
+func (s *StatusesRepository) Create(ctx context.Context, status entity.Status) (int, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (user_id, project_id, name, completed, position, transitions) "+
+			"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+			collectionStatuses)
+	)
+
+	err = tx.QueryRowContext(ctx, query, status.UserID, status.ProjectID, status.Name, status.Completed, status.Position,
+		pq.Array(status.Transitions)).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (s *StatusesRepository) GetByID(ctx context.Context, id, userId int) (entity.Status, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Status{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, project_id, name, completed, position, transitions FROM %s WHERE id = $1 AND user_id = $2",
+		collectionStatuses)
+
+	status, err := scanStatus(tx.QueryRowContext(ctx, query, id, userId))
+	if err != nil {
+		return entity.Status{}, err
+	}
+
+	return status, tx.Commit()
+}
+
+// GetByProjectID returns the statuses configured exactly for the project (or user-wide ones, if projectId is nil).
+func (s *StatusesRepository) GetByProjectID(ctx context.Context, userId int, projectId *int) ([]entity.Status, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, project_id, name, completed, position, transitions FROM %s "+
+		"WHERE user_id = $1 AND project_id IS NOT DISTINCT FROM $2 ORDER BY position, id",
+		collectionStatuses)
+
+	rows, err := tx.QueryContext(ctx, query, userId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var statuses []entity.Status
+	for rows.Next() {
+		status, err := scanStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses, tx.Commit()
+}
+
+// GetByUserID returns the statuses of all the user's workflows.
+func (s *StatusesRepository) GetByUserID(ctx context.Context, userId int) ([]entity.Status, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT id, project_id, name, completed, position, transitions FROM %s "+
+		"WHERE user_id = $1 ORDER BY project_id NULLS FIRST, position, id",
+		collectionStatuses)
+
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var statuses []entity.Status
+	for rows.Next() {
+		status, err := scanStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses, tx.Commit()
+}
+
+// Update updates the status, when it is renamed the tasks of its workflow are moved to the new name too.
+func (s *StatusesRepository) Update(ctx context.Context, status entity.Status, oldName string) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET name = $1, completed = $2, position = $3, transitions = $4 WHERE id = $5 AND user_id = $6",
+		collectionStatuses)
+
+	_, err = tx.ExecContext(ctx, query, status.Name, status.Completed, status.Position, pq.Array(status.Transitions),
+		status.ID, status.UserID)
+	if err != nil {
+		return err
+	}
+
+	if status.Name != oldName {
+		scope, scopeArgs := statusScopeCondition(4, status.ProjectID)
+
+		query = fmt.Sprintf("UPDATE %s SET status = $1 WHERE user_id = $2 AND status = $3 AND %s RETURNING id",
+			collectionAgenda, scope)
+
+		ids, err := queryIDs(ctx, tx, query, append([]any{status.Name, status.UserID, oldName}, scopeArgs...)...)
+		if err != nil {
+			return err
+		}
+
+		entries := historyEntries(ids, status.UserID, entity.HistoryStatusChanged)
+		for i := range entries {
+			entries[i].Changes = map[string]entity.FieldChange{"status": {Old: oldName, New: status.Name}}
+		}
+
+		if err = recordHistory(ctx, tx, entries...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *StatusesRepository) DeleteByID(ctx context.Context, id, userId int) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", collectionStatuses)
+
+	_, err = tx.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsUsed reports whether any task of the status workflow has this status.
+func (s *StatusesRepository) IsUsed(ctx context.Context, status entity.Status) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		used             bool
+		scope, scopeArgs = statusScopeCondition(3, status.ProjectID)
+		query            = fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE user_id = $1 AND status = $2 AND %s)",
+			collectionAgenda, scope)
+	)
+
+	err = tx.QueryRowContext(ctx, query, append([]any{status.UserID, status.Name}, scopeArgs...)...).Scan(&used)
+	if err != nil {
+		return false, err
+	}
+
+	return used, tx.Commit()
+}
+
+// statusScopeCondition returns the condition selecting tasks the workflow of the project applies to:
+// the tasks of the project, or (for user-wide statuses) the tasks of projects without their own statuses.
+// The user id is expected to be the $2 placeholder, argId is the number of the next placeholder.
+func statusScopeCondition(argId int, projectId *int) (string, []any) {
+	if projectId != nil {
+		return fmt.Sprintf("project_id = $%d", argId), []any{*projectId}
+	}
+
+	return fmt.Sprintf("(project_id IS NULL OR project_id NOT IN (SELECT project_id FROM %s WHERE user_id = $2 AND project_id IS NOT NULL))",
+		collectionStatuses), nil
+}
+
+func scanStatus(row rowScanner) (entity.Status, error) {
+	var status entity.Status
+
+	err := row.Scan(&status.ID, &status.ProjectID, &status.Name, &status.Completed, &status.Position, pq.Array(&status.Transitions))
+	if err != nil {
+		return entity.Status{}, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,460 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+const testStatusOwnScope = "(project_id IS NULL OR project_id NOT IN " +
+	"(SELECT project_id FROM statuses WHERE user_id = $2 AND project_id IS NOT NULL))"
+
+func TestStatusesRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewStatuses(db)
+
+	type args struct {
+		status entity.Status
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				status: entity.Status{UserID: 1, Name: "in review", Position: 2, Transitions: []string{"done", "in progress"}},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO statuses (user_id, project_id, name, completed, position, transitions) " +
+					"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.status.UserID, args.status.ProjectID, args.status.Name, args.status.Completed,
+						args.status.Position, pq.Array(args.status.Transitions)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				status: entity.Status{UserID: 1, Name: "done", Completed: true},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO statuses (user_id, project_id, name, completed, position, transitions) " +
+					"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.status.UserID, args.status.ProjectID, args.status.Name, args.status.Completed,
+						args.status.Position, pq.Array(args.status.Transitions)).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.status)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestStatusesRepository_GetByProjectID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewStatuses(db)
+
+	testProjectID := 3
+
+	type args struct {
+		userId    int
+		projectId *int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.Status
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId:    1,
+				projectId: &testProjectID,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT id, project_id, name, completed, position, transitions FROM statuses " +
+					"WHERE user_id = $1 AND project_id IS NOT DISTINCT FROM $2 ORDER BY position, id"
+				rows := sqlmock.NewRows([]string{"id", "project_id", "name", "completed", "position", "transitions"}).
+					AddRow(1, testProjectID, "todo", false, 0, "{\"in progress\"}").
+					AddRow(2, testProjectID, "in progress", false, 1, nil).
+					AddRow(3, testProjectID, "done", true, 2, "{}")
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, args.projectId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.Status{
+				{ID: 1, ProjectID: &testProjectID, Name: "todo", Position: 0, Transitions: []string{"in progress"}},
+				{ID: 2, ProjectID: &testProjectID, Name: "in progress", Position: 1},
+				{ID: 3, ProjectID: &testProjectID, Name: "done", Completed: true, Position: 2, Transitions: []string{}},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT id, project_id, name, completed, position, transitions FROM statuses " +
+					"WHERE user_id = $1 AND project_id IS NOT DISTINCT FROM $2 ORDER BY position, id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, args.projectId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByProjectID(context.Background(), tt.args.userId, tt.args.projectId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatusesRepository_GetByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewStatuses(db)
+
+	testProjectID := 3
+
+	type args struct {
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	const expectedQuery = "SELECT id, project_id, name, completed, position, transitions FROM statuses " +
+		"WHERE user_id = $1 ORDER BY project_id NULLS FIRST, position, id"
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.Status
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "project_id", "name", "completed", "position", "transitions"}).
+					AddRow(1, nil, "in progress", false, 0, nil).
+					AddRow(2, testProjectID, "review", false, 0, nil).
+					AddRow(3, testProjectID, "shipped", true, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.Status{
+				{ID: 1, Name: "in progress", Position: 0},
+				{ID: 2, ProjectID: &testProjectID, Name: "review", Position: 0},
+				{ID: 3, ProjectID: &testProjectID, Name: "shipped", Completed: true, Position: 1},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByUserID(context.Background(), tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatusesRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewStatuses(db)
+
+	testProjectID := 3
+
+	type args struct {
+		status  entity.Status
+		oldName string
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				status:  entity.Status{ID: 1, UserID: 1, Name: "todo", Position: 1},
+				oldName: "todo",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE statuses SET name = $1, completed = $2, position = $3, transitions = $4 WHERE id = $5 AND user_id = $6"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.status.Name, args.status.Completed, args.status.Position, pq.Array(args.status.Transitions),
+						args.status.ID, args.status.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK renamed",
+			args: args{
+				status:  entity.Status{ID: 1, UserID: 1, Name: "backlog"},
+				oldName: "todo",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE statuses SET name = $1, completed = $2, position = $3, transitions = $4 WHERE id = $5 AND user_id = $6"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.status.Name, args.status.Completed, args.status.Position, pq.Array(args.status.Transitions),
+						args.status.ID, args.status.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				expectedQuery := "UPDATE agenda SET status = $1 WHERE user_id = $2 AND status = $3 AND " + testStatusOwnScope + " RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status.Name, args.status.UserID, args.oldName).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				expectedExec = "INSERT INTO task_history (task_id, user_id, action, changes) VALUES ($1, $2, $3, $4)"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(1, args.status.UserID, entity.HistoryStatusChanged, `{"status":{"old":"todo","new":"backlog"}}`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK renamed in project",
+			args: args{
+				status:  entity.Status{ID: 1, UserID: 1, ProjectID: &testProjectID, Name: "backlog"},
+				oldName: "todo",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE statuses SET name = $1, completed = $2, position = $3, transitions = $4 WHERE id = $5 AND user_id = $6"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.status.Name, args.status.Completed, args.status.Position, pq.Array(args.status.Transitions),
+						args.status.ID, args.status.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				expectedQuery := "UPDATE agenda SET status = $1 WHERE user_id = $2 AND status = $3 AND project_id = $4 RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status.Name, args.status.UserID, args.oldName, testProjectID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				status:  entity.Status{ID: 1, UserID: 1, Name: "backlog"},
+				oldName: "todo",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE statuses SET name = $1, completed = $2, position = $3, transitions = $4 WHERE id = $5 AND user_id = $6"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.status.Name, args.status.Completed, args.status.Position, pq.Array(args.status.Transitions),
+						args.status.ID, args.status.UserID).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Update(context.Background(), tt.args.status, tt.args.oldName)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStatusesRepository_IsUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewStatuses(db)
+
+	type args struct {
+		status entity.Status
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          bool
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				status: entity.Status{ID: 1, UserID: 1, Name: "todo"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT EXISTS (SELECT 1 FROM agenda WHERE user_id = $1 AND status = $2 AND " + testStatusOwnScope + ")"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status.UserID, args.status.Name).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+				mock.ExpectCommit()
+			},
+			want: true,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				status: entity.Status{ID: 1, UserID: 1, Name: "todo"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT EXISTS (SELECT 1 FROM agenda WHERE user_id = $1 AND status = $2 AND " + testStatusOwnScope + ")"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.status.UserID, args.status.Name).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.IsUsed(context.Background(), tt.args.status)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
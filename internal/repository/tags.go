@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+type TagsRepository struct {
+	db *sql.DB
+}
+
+func NewTags(db *sql.DB) *TagsRepository {
+	return &TagsRepository{db: db}
+}
+
+func (t *TagsRepository) Create(ctx context.Context, tag entity.Tag) (int, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (user_id, name) VALUES ($1, $2) RETURNING id",
+			collectionTags)
+	)
+
+	err = tx.QueryRowContext(ctx, query, tag.UserID, tag.Name).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (t *TagsRepository) GetByID(ctx context.Context, id, userId int) (entity.Tag, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Tag{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		tag   entity.Tag
+		query = fmt.Sprintf("SELECT id, name FROM %s WHERE id = $1 AND user_id = $2",
+			collectionTags)
+	)
+
+	err = tx.QueryRowContext(ctx, query, id, userId).Scan(&tag.ID, &tag.Name)
+	if err != nil {
+		return entity.Tag{}, err
+	}
+
+	return tag, tx.Commit()
+}
+
+func (t *TagsRepository) GetByUserID(ctx context.Context, userId int) ([]entity.Tag, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		tags  []entity.Tag
+		query = fmt.Sprintf("SELECT id, name FROM %s WHERE user_id = $1 ORDER BY name",
+			collectionTags)
+	)
+
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var tag entity.Tag
+		if err = rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, tx.Commit()
+}
+
+func (t *TagsRepository) Rename(ctx context.Context, id, userId int, name string) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET name = $1 WHERE id = $2 AND user_id = $3",
+		collectionTags)
+
+	_, err = tx.ExecContext(ctx, query, name, id, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *TagsRepository) DeleteByID(ctx context.Context, id, userId int) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var query = fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", collectionTags)
+
+	_, err = tx.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+const templateColumns = "id, user_id, name, title, description, due_offset_minutes, tags, checklist"
+
+type TemplatesRepository struct {
+	db *sql.DB
+}
+
+func NewTemplates(db *sql.DB) *TemplatesRepository {
+	return &TemplatesRepository{db: db}
+}
+
+func (t *TemplatesRepository) Create(ctx context.Context, template entity.Template) (int, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (user_id, name, title, description, due_offset_minutes, tags, checklist) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id", collectionTemplates)
+	)
+
+	err = tx.QueryRowContext(ctx, query, template.UserID, template.Name, template.Title, template.Description,
+		template.DueOffsetMinutes, pq.Array(template.Tags), pq.Array(template.Checklist)).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (t *TemplatesRepository) GetByID(ctx context.Context, id, userId int) (entity.Template, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.Template{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND user_id = $2", templateColumns, collectionTemplates)
+
+	template, err := scanTemplate(tx.QueryRowContext(ctx, query, id, userId))
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	return template, tx.Commit()
+}
+
+func (t *TemplatesRepository) GetByUserID(ctx context.Context, userId int) ([]entity.Template, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 ORDER BY name", templateColumns, collectionTemplates)
+
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var templates []entity.Template
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, tx.Commit()
+}
+
+func (t *TemplatesRepository) Update(ctx context.Context, template entity.Template) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET name = $1, title = $2, description = $3, due_offset_minutes = $4, tags = $5, checklist = $6 "+
+		"WHERE id = $7 AND user_id = $8", collectionTemplates)
+
+	_, err = tx.ExecContext(ctx, query, template.Name, template.Title, template.Description, template.DueOffsetMinutes,
+		pq.Array(template.Tags), pq.Array(template.Checklist), template.ID, template.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *TemplatesRepository) DeleteByID(ctx context.Context, id, userId int) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", collectionTemplates)
+
+	_, err = tx.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanTemplate(row rowScanner) (entity.Template, error) {
+	var template entity.Template
+
+	err := row.Scan(&template.ID, &template.UserID, &template.Name, &template.Title, &template.Description,
+		&template.DueOffsetMinutes, pq.Array(&template.Tags), pq.Array(&template.Checklist))
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	return template, nil
+}
@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+const testTemplateColumns = "id, user_id, name, title, description, due_offset_minutes, tags, checklist"
+
+var testTemplateRows = []string{"id", "user_id", "name", "title", "description", "due_offset_minutes", "tags", "checklist"}
+
+func TestTemplatesRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTemplates(db)
+
+	expectedQuery := "INSERT INTO templates (user_id, name, title, description, due_offset_minutes, tags, checklist) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+
+	type args struct {
+		template entity.Template
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				template: entity.Template{UserID: 1, Name: "Release", Title: "Release {{version}}", DueOffsetMinutes: 1440,
+					Tags: []string{"work"}, Checklist: []string{"Tag", "Deploy"}},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.template.UserID, args.template.Name, args.template.Title, args.template.Description,
+						args.template.DueOffsetMinutes, pq.Array(args.template.Tags), pq.Array(args.template.Checklist)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "ERROR",
+			args: args{
+				template: entity.Template{UserID: 1, Name: "Release", Title: "Release"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.template.UserID, args.template.Name, args.template.Title, args.template.Description,
+						args.template.DueOffsetMinutes, pq.Array(args.template.Tags), pq.Array(args.template.Checklist)).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.template)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestTemplatesRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTemplates(db)
+
+	expectedQuery := "SELECT " + testTemplateColumns + " FROM templates WHERE id = $1 AND user_id = $2"
+
+	type args struct {
+		id     int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantTemplate  entity.Template
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testTemplateRows).
+					AddRow(args.id, args.userId, "Release", "Release {{version}}", "", 1440, "{work}", "{Tag,Deploy}")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantTemplate: entity.Template{ID: 1, UserID: 1, Name: "Release", Title: "Release {{version}}", DueOffsetMinutes: 1440,
+				Tags: []string{"work"}, Checklist: []string{"Tag", "Deploy"}},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			template, err := repo.GetByID(context.Background(), tt.args.id, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTemplate, template)
+			}
+		})
+	}
+}
+
+func TestTemplatesRepository_GetByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTemplates(db)
+
+	expectedQuery := "SELECT " + testTemplateColumns + " FROM templates WHERE user_id = $1 ORDER BY name"
+
+	type args struct {
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantTemplates []entity.Template
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testTemplateRows).
+					AddRow(2, args.userId, "Groceries", "Buy groceries", "", 0, "{home}", "{}").
+					AddRow(1, args.userId, "Release", "Release {{version}}", "", 1440, "{}", "{Tag,Deploy}")
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantTemplates: []entity.Template{
+				{ID: 2, UserID: 1, Name: "Groceries", Title: "Buy groceries", Tags: []string{"home"}, Checklist: []string{}},
+				{ID: 1, UserID: 1, Name: "Release", Title: "Release {{version}}", DueOffsetMinutes: 1440,
+					Tags: []string{}, Checklist: []string{"Tag", "Deploy"}},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userId: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			templates, err := repo.GetByUserID(context.Background(), tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTemplates, templates)
+			}
+		})
+	}
+}
+
+func TestTemplatesRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTemplates(db)
+
+	expectedQuery := "UPDATE templates SET name = $1, title = $2, description = $3, due_offset_minutes = $4, tags = $5, checklist = $6 " +
+		"WHERE id = $7 AND user_id = $8"
+
+	type args struct {
+		template entity.Template
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				template: entity.Template{ID: 1, UserID: 1, Name: "Release", Title: "Release v{{version}}", Checklist: []string{"Tag"}},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.template.Name, args.template.Title, args.template.Description, args.template.DueOffsetMinutes,
+						pq.Array(args.template.Tags), pq.Array(args.template.Checklist), args.template.ID, args.template.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				template: entity.Template{ID: 2, UserID: 1, Name: "Release", Title: "Release"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.template.Name, args.template.Title, args.template.Description, args.template.DueOffsetMinutes,
+						pq.Array(args.template.Tags), pq.Array(args.template.Checklist), args.template.ID, args.template.UserID).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Update(context.Background(), tt.args.template)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTemplatesRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTemplates(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "DELETE FROM templates WHERE id = $1 AND user_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "DELETE FROM templates WHERE id = $1 AND user_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
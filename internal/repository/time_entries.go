@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+// timeEntryDuration is the duration of the entry in seconds, a running timer is counted up to now.
+const timeEntryDuration = "EXTRACT(EPOCH FROM COALESCE(stopped_at, NOW()) - started_at)::BIGINT"
+
+var timeEntryColumns = "id, task_id, user_id, started_at, stopped_at, note, " + timeEntryDuration
+
+// reportDays is the CTE of the calendar days from $2 to $3 (inclusive) with their bounds in the $4 timezone.
+const reportDays = "days AS (SELECT day::DATE AS date, day AT TIME ZONE $4 AS start, (day + INTERVAL '1 day') AT TIME ZONE $4 AS finish " +
+	"FROM GENERATE_SERIES($2::DATE, $3::DATE, INTERVAL '1 day') AS day)"
+
+type TimeEntriesRepository struct {
+	db *sql.DB
+}
+
+func NewTimeEntries(db *sql.DB) *TimeEntriesRepository {
+	return &TimeEntriesRepository{db: db}
+}
+
+// Create adds the entry, an entry without StoppedAt is a running timer. If the user already has
+// a running timer, nothing is created and sql.ErrNoRows is returned.
+func (t *TimeEntriesRepository) Create(ctx context.Context, entry entity.TimeEntry) (int, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		id    int
+		query = fmt.Sprintf("INSERT INTO %s (task_id, user_id, started_at, stopped_at, note) VALUES ($1, $2, $3, $4, $5) "+
+			"ON CONFLICT (user_id) WHERE stopped_at IS NULL DO NOTHING RETURNING id", collectionTimeEntries)
+	)
+
+	err = tx.QueryRowContext(ctx, query, entry.TaskID, entry.UserID, entry.StartedAt, entry.StoppedAt, entry.Note).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (t *TimeEntriesRepository) GetByID(ctx context.Context, id, taskId, userId int) (entity.TimeEntry, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return entity.TimeEntry{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND task_id = $2 AND user_id = $3", timeEntryColumns, collectionTimeEntries)
+
+	entry, err := scanTimeEntry(tx.QueryRowContext(ctx, query, id, taskId, userId))
+	if err != nil {
+		return entity.TimeEntry{}, err
+	}
+
+	return entry, tx.Commit()
+}
+
+func (t *TimeEntriesRepository) GetByTaskID(ctx context.Context, taskId, userId int) ([]entity.TimeEntry, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE task_id = $1 AND user_id = $2 ORDER BY started_at, id",
+		timeEntryColumns, collectionTimeEntries)
+
+	rows, err := tx.QueryContext(ctx, query, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []entity.TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, tx.Commit()
+}
+
+// Stop stops the running timer of the user, sql.ErrNoRows is returned if no timer is running.
+func (t *TimeEntriesRepository) Stop(ctx context.Context, userId int) (entity.TimeEntry, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.TimeEntry{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET stopped_at = GREATEST(NOW(), started_at) WHERE user_id = $1 AND stopped_at IS NULL RETURNING %s",
+		collectionTimeEntries, timeEntryColumns)
+
+	entry, err := scanTimeEntry(tx.QueryRowContext(ctx, query, userId))
+	if err != nil {
+		return entity.TimeEntry{}, err
+	}
+
+	return entry, tx.Commit()
+}
+
+func (t *TimeEntriesRepository) Update(ctx context.Context, id, taskId, userId int, update entity.TimeEntryUpdate) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		values = make([]string, 0)
+		args   = make([]any, 0)
+		argId  = 1
+	)
+
+	if update.StartedAt != nil {
+		values = append(values, fmt.Sprintf("started_at = $%d", argId))
+		args = append(args, *update.StartedAt)
+		argId++
+	}
+
+	if update.StoppedAt != nil {
+		values = append(values, fmt.Sprintf("stopped_at = $%d", argId))
+		args = append(args, *update.StoppedAt)
+		argId++
+	}
+
+	if update.Note != nil {
+		values = append(values, fmt.Sprintf("note = $%d", argId))
+		args = append(args, *update.Note)
+		argId++
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND task_id = $%d AND user_id = $%d",
+		collectionTimeEntries, strings.Join(values, ", "), argId, argId+1, argId+2)
+	args = append(args, id, taskId, userId)
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *TimeEntriesRepository) DeleteByID(ctx context.Context, id, taskId, userId int) error {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND task_id = $2 AND user_id = $3", collectionTimeEntries)
+
+	_, err = tx.ExecContext(ctx, query, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetReport returns the time tracked by the user per day and per task, from and to are the starts of the first
+// and the last day of the report in the user's location. Entries spanning midnight are split between the days.
+func (t *TimeEntriesRepository) GetReport(ctx context.Context, userId int, from, to time.Time) ([]entity.TimeReportRow, error) {
+	tx, err := t.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("WITH %[3]s SELECT TO_CHAR(days.date, 'YYYY-MM-DD'), %[1]s.task_id, %[2]s.title, "+
+		"SUM(EXTRACT(EPOCH FROM LEAST(COALESCE(%[1]s.stopped_at, NOW()), days.finish) - GREATEST(%[1]s.started_at, days.start)))::BIGINT "+
+		"FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.task_id "+
+		"JOIN days ON %[1]s.started_at < days.finish AND COALESCE(%[1]s.stopped_at, NOW()) > days.start "+
+		"WHERE %[1]s.user_id = $1 GROUP BY days.date, %[1]s.task_id, %[2]s.title ORDER BY days.date, %[1]s.task_id",
+		collectionTimeEntries, collectionAgenda, reportDays)
+
+	rows, err := tx.QueryContext(ctx, query, userId, from.Format(time.DateOnly), to.Format(time.DateOnly), from.Location().String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var report []entity.TimeReportRow
+	for rows.Next() {
+		var row entity.TimeReportRow
+		if err = rows.Scan(&row.Date, &row.TaskID, &row.TaskTitle, &row.Duration); err != nil {
+			return nil, err
+		}
+		report = append(report, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return report, tx.Commit()
+}
+
+func scanTimeEntry(row rowScanner) (entity.TimeEntry, error) {
+	var entry entity.TimeEntry
+
+	err := row.Scan(&entry.ID, &entry.TaskID, &entry.UserID, &entry.StartedAt, &entry.StoppedAt, &entry.Note, &entry.Duration)
+	if err != nil {
+		return entity.TimeEntry{}, err
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,557 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+var testTimeEntryRows = []string{"id", "task_id", "user_id", "started_at", "stopped_at", "note", "duration"}
+
+const testTimeEntryColumns = "id, task_id, user_id, started_at, stopped_at, note, EXTRACT(EPOCH FROM COALESCE(stopped_at, NOW()) - started_at)::BIGINT"
+
+func TestTimeEntriesRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTimeEntries(db)
+
+	var (
+		testStartedAt = time.Now().Round(time.Second)
+		testStoppedAt = testStartedAt.Add(time.Hour)
+		expectedQuery = "INSERT INTO time_entries (task_id, user_id, started_at, stopped_at, note) VALUES ($1, $2, $3, $4, $5) " +
+			"ON CONFLICT (user_id) WHERE stopped_at IS NULL DO NOTHING RETURNING id"
+	)
+
+	type args struct {
+		entry entity.TimeEntry
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantID        int
+		wantErr       error
+	}{
+		{
+			name: "OK timer",
+			args: args{
+				entry: entity.TimeEntry{TaskID: 1, UserID: 1, StartedAt: testStartedAt},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.entry.TaskID, args.entry.UserID, args.entry.StartedAt, nil, "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			wantID: 1,
+		},
+		{
+			name: "OK manual",
+			args: args{
+				entry: entity.TimeEntry{TaskID: 1, UserID: 1, StartedAt: testStartedAt, StoppedAt: &testStoppedAt, Note: "Call with the client"},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.entry.TaskID, args.entry.UserID, args.entry.StartedAt, args.entry.StoppedAt, args.entry.Note).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+				mock.ExpectCommit()
+			},
+			wantID: 2,
+		},
+		{
+			name: "ERROR timer is already running",
+			args: args{
+				entry: entity.TimeEntry{TaskID: 2, UserID: 1, StartedAt: testStartedAt},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.entry.TaskID, args.entry.UserID, args.entry.StartedAt, nil, "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			id, err := repo.Create(context.Background(), tt.args.entry)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestTimeEntriesRepository_GetByTaskID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTimeEntries(db)
+
+	var (
+		testStartedAt = time.Now().Add(-3 * time.Hour).Round(time.Second)
+		testStoppedAt = testStartedAt.Add(time.Hour)
+		expectedQuery = "SELECT " + testTimeEntryColumns + " FROM time_entries WHERE task_id = $1 AND user_id = $2 ORDER BY started_at, id"
+	)
+
+	type args struct {
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.TimeEntry
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testTimeEntryRows).
+					AddRow(1, args.taskId, args.userId, testStartedAt, testStoppedAt, "Call with the client", 3600).
+					AddRow(2, args.taskId, args.userId, testStoppedAt, nil, "", 7200)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.TimeEntry{
+				{ID: 1, TaskID: 1, UserID: 1, StartedAt: testStartedAt, StoppedAt: &testStoppedAt, Note: "Call with the client", Duration: 3600},
+				{ID: 2, TaskID: 1, UserID: 1, StartedAt: testStoppedAt, Duration: 7200},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				taskId: 2,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetByTaskID(context.Background(), tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTimeEntriesRepository_Stop(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTimeEntries(db)
+
+	var (
+		testStartedAt = time.Now().Add(-time.Hour).Round(time.Second)
+		testStoppedAt = testStartedAt.Add(time.Hour)
+		expectedQuery = "UPDATE time_entries SET stopped_at = GREATEST(NOW(), started_at) WHERE user_id = $1 AND stopped_at IS NULL " +
+			"RETURNING " + testTimeEntryColumns
+	)
+
+	type args struct {
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          entity.TimeEntry
+		wantErr       error
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(testTimeEntryRows).
+					AddRow(1, 1, args.userId, testStartedAt, testStoppedAt, "", 3600)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: entity.TimeEntry{ID: 1, TaskID: 1, UserID: 1, StartedAt: testStartedAt, StoppedAt: &testStoppedAt, Duration: 3600},
+		},
+		{
+			name: "ERROR timer is not running",
+			args: args{
+				userId: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows(testTimeEntryRows))
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.Stop(context.Background(), tt.args.userId)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTimeEntriesRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTimeEntries(db)
+
+	var (
+		testStartedAt = time.Now().Add(-time.Hour).Round(time.Second)
+		testStoppedAt = testStartedAt.Add(30 * time.Minute)
+		testNote      = "Review"
+	)
+
+	type args struct {
+		id     int
+		taskId int
+		userId int
+		update entity.TimeEntryUpdate
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				taskId: 1,
+				userId: 1,
+				update: entity.TimeEntryUpdate{StartedAt: &testStartedAt, StoppedAt: &testStoppedAt, Note: &testNote},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE time_entries SET started_at = $1, stopped_at = $2, note = $3 WHERE id = $4 AND task_id = $5 AND user_id = $6"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testStartedAt, testStoppedAt, testNote, args.id, args.taskId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK note only",
+			args: args{
+				id:     1,
+				taskId: 1,
+				userId: 1,
+				update: entity.TimeEntryUpdate{Note: &testNote},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE time_entries SET note = $1 WHERE id = $2 AND task_id = $3 AND user_id = $4"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testNote, args.id, args.taskId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				taskId: 1,
+				userId: 1,
+				update: entity.TimeEntryUpdate{Note: &testNote},
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE time_entries SET note = $1 WHERE id = $2 AND task_id = $3 AND user_id = $4"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(testNote, args.id, args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.Update(context.Background(), tt.args.id, tt.args.taskId, tt.args.userId, tt.args.update)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTimeEntriesRepository_DeleteByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTimeEntries(db)
+
+	expectedExec := "DELETE FROM time_entries WHERE id = $1 AND task_id = $2 AND user_id = $3"
+
+	type args struct {
+		id     int
+		taskId int
+		userId int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:     1,
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.taskId, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:     2,
+				taskId: 1,
+				userId: 1,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.id, args.taskId, args.userId).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.DeleteByID(context.Background(), tt.args.id, tt.args.taskId, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTimeEntriesRepository_GetReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewTimeEntries(db)
+
+	location, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("error loading location: %v\n", err)
+	}
+
+	var (
+		testFrom      = time.Date(2026, 10, 5, 0, 0, 0, 0, location)
+		testTo        = time.Date(2026, 10, 11, 0, 0, 0, 0, location)
+		expectedQuery = "WITH days AS (SELECT day::DATE AS date, day AT TIME ZONE $4 AS start, (day + INTERVAL '1 day') AT TIME ZONE $4 AS finish " +
+			"FROM GENERATE_SERIES($2::DATE, $3::DATE, INTERVAL '1 day') AS day) " +
+			"SELECT TO_CHAR(days.date, 'YYYY-MM-DD'), time_entries.task_id, agenda.title, " +
+			"SUM(EXTRACT(EPOCH FROM LEAST(COALESCE(time_entries.stopped_at, NOW()), days.finish) - GREATEST(time_entries.started_at, days.start)))::BIGINT " +
+			"FROM time_entries JOIN agenda ON agenda.id = time_entries.task_id " +
+			"JOIN days ON time_entries.started_at < days.finish AND COALESCE(time_entries.stopped_at, NOW()) > days.start " +
+			"WHERE time_entries.user_id = $1 GROUP BY days.date, time_entries.task_id, agenda.title ORDER BY days.date, time_entries.task_id"
+	)
+
+	type args struct {
+		userId int
+		from   time.Time
+		to     time.Time
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		args          args
+		mockBehaviour mockBehaviour
+		want          []entity.TimeReportRow
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+				from:   testFrom,
+				to:     testTo,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"date", "task_id", "title", "duration"}).
+					AddRow("2026-10-05", 1, "Landing page", 5400).
+					AddRow("2026-10-06", 1, "Landing page", 1800).
+					AddRow("2026-10-06", 2, "Support", 600)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, "2026-10-05", "2026-10-11", "Asia/Tokyo").
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			want: []entity.TimeReportRow{
+				{Date: "2026-10-05", TaskID: 1, TaskTitle: "Landing page", Duration: 5400},
+				{Date: "2026-10-06", TaskID: 1, TaskTitle: "Landing page", Duration: 1800},
+				{Date: "2026-10-06", TaskID: 2, TaskTitle: "Support", Duration: 600},
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				userId: 2,
+				from:   testFrom,
+				to:     testTo,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, "2026-10-05", "2026-10-11", "Asia/Tokyo").
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			got, err := repo.GetReport(context.Background(), tt.args.userId, tt.args.from, tt.args.to)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -28,11 +28,11 @@ func (u *UsersRepository) Create(ctx context.Context, user entity.User) (int, er
 
 	var (
 		id    int
-		query = fmt.Sprintf("INSERT INTO %s (login, email, password) VALUES ($1, $2, $3) RETURNING id",
+		query = fmt.Sprintf("INSERT INTO %s (login, email, password, timezone) VALUES ($1, $2, $3, $4) RETURNING id",
 			collectionUsers)
 	)
 
-	err = tx.QueryRowContext(ctx, query, user.Login, user.Email, user.Password).Scan(&id)
+	err = tx.QueryRowContext(ctx, query, user.Login, user.Email, user.Password, user.Timezone).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -160,3 +160,45 @@ func (u *UsersRepository) SetSession(ctx context.Context, userId int, session en
 
 	return tx.Commit()
 }
+
+func (u *UsersRepository) GetTimezone(ctx context.Context, id int) (string, error) {
+	tx, err := u.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		timezone string
+		query    = fmt.Sprintf("SELECT timezone FROM %s WHERE id = $1", collectionUsers)
+	)
+
+	if err = tx.QueryRowContext(ctx, query, id).Scan(&timezone); err != nil {
+		return "", err
+	}
+
+	return timezone, tx.Commit()
+}
+
+func (u *UsersRepository) SetTimezone(ctx context.Context, id int, timezone string) error {
+	tx, err := u.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf("UPDATE %s SET timezone = $1 WHERE id = $2", collectionUsers)
+
+	_, err = tx.ExecContext(ctx, query, timezone, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -48,9 +48,9 @@ func TestUsersRepository_Create(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO users (login, email, password) VALUES ($1, $2, $3) RETURNING id"
+				expectedExec := "INSERT INTO users (login, email, password, timezone) VALUES ($1, $2, $3, $4) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.user.Login, args.user.Email, args.user.Password).
+					WithArgs(args.user.Login, args.user.Email, args.user.Password, args.user.Timezone).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				mock.ExpectCommit()
@@ -66,7 +66,7 @@ func TestUsersRepository_Create(t *testing.T) {
 
 				expectedExec := "INSERT INTO users (login, email, password) VALUES ($1, $2, $3)"
 				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.user.Login, args.user.Email, args.user.Password).
+					WithArgs(args.user.Login, args.user.Email, args.user.Password, args.user.Timezone).
 					WillReturnError(fmt.Errorf("test error"))
 
 				mock.ExpectRollback()
@@ -328,3 +328,146 @@ func TestUsersRepository_GetByCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestUsersRepository_GetTimezone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewUsers(db)
+
+	type args struct {
+		id int
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		args          args
+		timezone      string
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id: 1,
+			},
+			timezone: "Asia/Tokyo",
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"timezone"}).AddRow("Asia/Tokyo")
+
+				expectedQuery := "SELECT timezone FROM users WHERE id = $1"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id: 2,
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT timezone FROM users WHERE id = $1"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			timezone, err := repo.GetTimezone(context.Background(), tt.args.id)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.timezone, timezone)
+			}
+		})
+	}
+}
+
+func TestUsersRepository_SetTimezone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating database connection: %v\n", err)
+	}
+	defer db.Close()
+
+	repo := NewUsers(db)
+
+	type args struct {
+		id       int
+		timezone string
+	}
+	type mockBehaviour func(args args)
+
+	tests := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		args          args
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				id:       1,
+				timezone: "Asia/Tokyo",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE users SET timezone = $1 WHERE id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.timezone, args.id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ERROR",
+			args: args{
+				id:       2,
+				timezone: "Europe/Moscow",
+			},
+			mockBehaviour: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "UPDATE users SET timezone = $1 WHERE id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.timezone, args.id).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour(tt.args)
+			err := repo.SetTimezone(context.Background(), tt.args.id, tt.args.timezone)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,33 +4,92 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/zenorachi/todo-service/internal/entity"
 	"github.com/zenorachi/todo-service/internal/repository"
+	filterql "github.com/zenorachi/todo-service/pkg/filter"
+	"github.com/zenorachi/todo-service/pkg/rrule"
+)
+
+const (
+	// defaultUpcomingDays is the number of days of the upcoming view, today included.
+	defaultUpcomingDays = 7
+	maxUpcomingDays     = 366
 )
 
 type AgendaService struct {
-	repo repository.Agenda
+	repo         repository.Agenda
+	projects     repository.Projects
+	users        repository.Users
+	statuses     repository.Statuses
+	dependencies repository.Dependencies
+	checklists   repository.Checklists
 }
 
-func NewAgenda(repo repository.Agenda) *AgendaService {
-	return &AgendaService{repo: repo}
+func NewAgenda(repo repository.Agenda, projects repository.Projects, users repository.Users,
+	statuses repository.Statuses, dependencies repository.Dependencies, checklists repository.Checklists) *AgendaService {
+	return &AgendaService{
+		repo:         repo,
+		projects:     projects,
+		users:        users,
+		statuses:     statuses,
+		dependencies: dependencies,
+		checklists:   checklists,
+	}
 }
 
 func (a *AgendaService) CreateTask(ctx context.Context, task entity.Task) (int, error) {
-	if a.isTaskExists(ctx, task.Title, task.UserID) {
+	if task.ProjectID != nil && !a.isProjectExists(ctx, *task.ProjectID, task.UserID) {
+		return 0, entity.ErrProjectDoesNotExist
+	}
+
+	if task.ParentID != nil && !a.isTaskExists(ctx, *task.ParentID, task.UserID) {
+		return 0, entity.ErrParentTaskDoesNotExist
+	}
+
+	if a.isTitleTaken(ctx, task.Title, task.UserID, task.ProjectID) {
 		return 0, entity.ErrTaskAlreadyExist
 	}
 
-	if len(task.Status) != 0 && task.Status != entity.StatusDone && task.Status != entity.StatusNotDone {
-		return 0, entity.ErrInvalidStatus
+	workflow, err := workflowStatuses(ctx, a.statuses, task.UserID, task.ProjectID)
+	if err != nil {
+		return 0, err
 	}
 
 	if len(task.Status) == 0 {
-		task.Status = entity.StatusNotDone
+		task.Status = initialStatus(workflow).Name
 	}
 
+	status, ok := findStatus(workflow, task.Status)
+	if !ok {
+		return 0, entity.ErrInvalidStatus
+	}
+
+	if status.Completed {
+		completedAt := time.Now()
+		task.CompletedAt = &completedAt
+	}
+
+	if len(task.Priority) == 0 {
+		task.Priority = entity.PriorityNone
+	}
+
+	if !entity.IsValidPriority(task.Priority) {
+		return 0, entity.ErrInvalidPriority
+	}
+
+	recurrence, err := normalizeRecurrence(task.Recurrence)
+	if err != nil {
+		return 0, err
+	}
+	task.Recurrence = recurrence
+
+	task.Tags = normalizeTags(task.Tags)
+
 	return a.repo.Create(ctx, task)
 }
 
@@ -43,62 +102,628 @@ func (a *AgendaService) GetTaskByID(ctx context.Context, id, userId int) (entity
 		return entity.Task{}, err
 	}
 
+	task.Checklist, err = a.checklists.GetByTaskID(ctx, id)
+	if err != nil {
+		return entity.Task{}, err
+	}
+	task.Progress = entity.ChecklistProgress(task.Checklist)
+
 	return task, nil
 }
 
-func (a *AgendaService) SetTaskStatus(ctx context.Context, id, userId int, status string) error {
-	if !a.isTaskExists(ctx, id, userId) {
+// SetTaskStatus sets the task status, a task blocked by not completed tasks is completed only if forced,
+// in this case the blockers are returned.
+func (a *AgendaService) SetTaskStatus(ctx context.Context, id, userId int, status string, cascade, force bool) ([]entity.Task, error) {
+	task, err := a.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := a.checkTransition(ctx, userId, task, resolveStatusAlias(status))
+	if err != nil {
+		return nil, err
+	}
+
+	var blockers []entity.Task
+	if task.CompletedAt == nil && target.Completed {
+		blockers, err = a.openBlockers(ctx, id, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(blockers) != 0 && !force {
+			return nil, entity.ErrTaskIsBlocked
+		}
+	}
+
+	var next *entity.Task
+	if task.CompletedAt == nil && target.Completed {
+		if next, err = a.nextOccurrence(ctx, userId, task); err != nil {
+			return nil, err
+		}
+	}
+
+	err = a.repo.SetStatus(ctx, id, userId, target.Name, target.Completed, cascade && target.Completed, next)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return blockers, nil
+}
+
+func (a *AgendaService) UpdateTask(ctx context.Context, id, userId int, update entity.TaskUpdate) (entity.Task, error) {
+	if update.IsEmpty() {
+		return entity.Task{}, entity.ErrEmptyTaskUpdate
+	}
+
+	task, err := a.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Task{}, entity.ErrTaskDoesNotExist
+	}
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	if update.Title != nil && *update.Title != task.Title && a.isTitleTaken(ctx, *update.Title, userId, task.ProjectID) {
+		return entity.Task{}, entity.ErrTaskAlreadyExist
+	}
+
+	if update.Status != nil {
+		target, err := a.checkTransition(ctx, userId, task, *update.Status)
+		if err != nil {
+			return entity.Task{}, err
+		}
+		update.Completed = &target.Completed
+
+		if task.CompletedAt == nil && target.Completed {
+			blockers, err := a.openBlockers(ctx, id, userId)
+			if err != nil {
+				return entity.Task{}, err
+			}
+
+			if len(blockers) != 0 {
+				return entity.Task{}, entity.ErrTaskIsBlocked
+			}
+		}
+	}
+
+	if update.Priority != nil && !entity.IsValidPriority(*update.Priority) {
+		return entity.Task{}, entity.ErrInvalidPriority
+	}
+
+	if update.Recurrence != nil {
+		recurrence, err := normalizeRecurrence(*update.Recurrence)
+		if err != nil {
+			return entity.Task{}, err
+		}
+		update.Recurrence = &recurrence
+	}
+
+	if update.Tags != nil {
+		tags := normalizeTags(*update.Tags)
+		update.Tags = &tags
+	}
+
+	var next *entity.Task
+	if task.CompletedAt == nil && update.Completed != nil && *update.Completed {
+		if next, err = a.nextOccurrence(ctx, userId, applyUpdate(task, update)); err != nil {
+			return entity.Task{}, err
+		}
+	}
+
+	updated, err := a.repo.Update(ctx, id, userId, update, next)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Task{}, entity.ErrTaskDoesNotExist
+	}
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	return updated, nil
+}
+
+func (a *AgendaService) MoveTask(ctx context.Context, id, userId int, projectId *int) error {
+	task, err := a.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
 		return entity.ErrTaskDoesNotExist
 	}
+	if err != nil {
+		return err
+	}
+
+	if isSameProject(task.ProjectID, projectId) {
+		return nil
+	}
+
+	if projectId != nil && !a.isProjectExists(ctx, *projectId, userId) {
+		return entity.ErrProjectDoesNotExist
+	}
 
-	if status != entity.StatusDone && status != entity.StatusNotDone {
-		return entity.ErrInvalidStatus
+	if a.isTitleTaken(ctx, task.Title, userId, projectId) {
+		return entity.ErrTaskAlreadyExist
 	}
 
-	return a.repo.SetStatus(ctx, id, userId, status)
+	return a.repo.SetProject(ctx, id, userId, projectId)
 }
 
-func (a *AgendaService) DeleteTaskByID(ctx context.Context, id, userId int) error {
+// ReorderTask puts the task right before or right after another task of the user.
+func (a *AgendaService) ReorderTask(ctx context.Context, id, userId int, beforeId, afterId *int) error {
+	if (beforeId == nil) == (afterId == nil) {
+		return entity.ErrInvalidMove
+	}
+
+	targetId := beforeId
+	if afterId != nil {
+		targetId = afterId
+	}
+
+	if *targetId == id {
+		return entity.ErrInvalidMove
+	}
+
+	if !a.isTaskExists(ctx, id, userId) || !a.isTaskExists(ctx, *targetId, userId) {
+		return entity.ErrTaskDoesNotExist
+	}
+
+	return a.repo.Move(ctx, id, userId, *targetId, beforeId != nil)
+}
+
+func (a *AgendaService) DeleteTaskByID(ctx context.Context, id, userId int, cascade bool) error {
 	if !a.isTaskExists(ctx, id, userId) {
 		return entity.ErrTaskDoesNotExist
 	}
 
-	return a.repo.DeleteByID(ctx, id, userId)
+	if !cascade {
+		subtasks, err := a.repo.GetSubtasks(ctx, id, userId)
+		if err != nil {
+			return err
+		}
+
+		if len(subtasks) != 0 {
+			return entity.ErrTaskHasSubtasks
+		}
+	}
+
+	return a.repo.DeleteByID(ctx, id, userId, cascade)
 }
 
 func (a *AgendaService) DeleteUserTasks(ctx context.Context, userId int) error {
 	return a.repo.DeleteByUserID(ctx, userId)
 }
 
-func (a *AgendaService) GetUserTasks(ctx context.Context, userId int) ([]entity.Task, error) {
-	return a.repo.GetByUserID(ctx, userId)
+func (a *AgendaService) GetSubtasks(ctx context.Context, id, userId int) ([]entity.Task, error) {
+	if !a.isTaskExists(ctx, id, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return a.repo.GetSubtasks(ctx, id, userId)
+}
+
+func (a *AgendaService) GetUserTasks(ctx context.Context, userId int, filter entity.TaskFilter) ([]entity.Task, error) {
+	if len(filter.Priority) != 0 && !entity.IsValidPriority(filter.Priority) {
+		return nil, entity.ErrInvalidPriority
+	}
+
+	filter.Tags = normalizeTags(filter.Tags)
+
+	if len(filter.Query) != 0 {
+		var err error
+		if filter.Location, err = userLocation(ctx, a.users, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks, err := a.repo.GetByUserID(ctx, userId, filter)
+
+	var queryErr *filterql.Error
+	if errors.As(err, &queryErr) {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidFilterQuery, queryErr)
+	}
+
+	if err != nil || !filter.AsTree {
+		return tasks, err
+	}
+
+	return buildTaskTree(tasks), nil
+}
+
+// ListTasks returns the tasks matching the filter, the view is resolved to the date bounds
+// relative to the current time in the user's location.
+func (a *AgendaService) ListTasks(ctx context.Context, userId int, filter entity.TaskFilter) (entity.TaskPage, error) {
+	if len(filter.View) != 0 {
+		var err error
+		if filter, err = a.resolveView(ctx, userId, filter); err != nil {
+			return entity.TaskPage{}, err
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return entity.TaskPage{}, entity.ErrInvalidDateRange
+	}
+
+	// the query selects the completed tasks by itself (is:completed, is:open)
+	if len(filter.Status) == 0 && len(filter.Query) == 0 && filter.Completed == nil {
+		completed := false
+		filter.Completed = &completed
+	}
+
+	if len(filter.Priority) != 0 && !entity.IsValidPriority(filter.Priority) {
+		return entity.TaskPage{}, entity.ErrInvalidPriority
+	}
+
+	if len(filter.Status) != 0 {
+		filter.Status = resolveStatusAlias(filter.Status)
+
+		known, err := isKnownStatus(ctx, a.statuses, userId, filter.ProjectID, filter.Status)
+		if err != nil {
+			return entity.TaskPage{}, err
+		}
+		if !known {
+			return entity.TaskPage{}, entity.ErrInvalidStatus
+		}
+	}
+
+	if filter.Limit < 1 || filter.Offset < 0 {
+		return entity.TaskPage{}, entity.ErrInvalidPaginationSizes
+	}
+
+	filter.Tags = normalizeTags(filter.Tags)
+
+	if len(filter.Query) != 0 {
+		var err error
+		if filter.Location, err = userLocation(ctx, a.users, userId); err != nil {
+			return entity.TaskPage{}, err
+		}
+	}
+
+	page, err := a.repo.List(ctx, userId, filter)
+
+	var queryErr *filterql.Error
+	if errors.As(err, &queryErr) {
+		return entity.TaskPage{}, fmt.Errorf("%w: %v", entity.ErrInvalidFilterQuery, queryErr)
+	}
+
+	if err != nil || !filter.AsTree {
+		return page, err
+	}
+
+	page.Tasks = buildTaskTree(page.Tasks)
+
+	return page, nil
+}
+
+func (a *AgendaService) resolveView(ctx context.Context, userId int, filter entity.TaskFilter) (entity.TaskFilter, error) {
+	location, err := userLocation(ctx, a.users, userId)
+	if err != nil {
+		return entity.TaskFilter{}, err
+	}
+
+	now := time.Now().In(location)
+
+	switch filter.View {
+	case entity.ViewOverdue:
+		completed := false
+		filter.Completed, filter.To = &completed, now
+	case entity.ViewUpcoming:
+		if filter.Days == 0 {
+			filter.Days = defaultUpcomingDays
+		}
+		if filter.Days < 1 || filter.Days > maxUpcomingDays {
+			return entity.TaskFilter{}, entity.ErrInvalidView
+		}
+
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+		filter.From, filter.To = now, today.AddDate(0, 0, filter.Days)
+	case entity.ViewNoDate:
+		filter.NoDate = true
+	default:
+		return entity.TaskFilter{}, entity.ErrInvalidView
+	}
+
+	return filter, nil
+}
+
+// GetWorkloadReport compares the estimates of the tasks planned per day with the completed ones,
+// from and to are the starts of the first and the last day of the report in the user's location.
+func (a *AgendaService) GetWorkloadReport(ctx context.Context, userId int, from, to time.Time) (entity.WorkloadReport, error) {
+	if to.Before(from) || to.Sub(from) > maxReportRange {
+		return entity.WorkloadReport{}, entity.ErrInvalidDateRange
+	}
+
+	days, err := a.repo.GetWorkload(ctx, userId, from, to)
+	if err != nil {
+		return entity.WorkloadReport{}, err
+	}
+
+	return entity.NewWorkloadReport(from.Format(time.DateOnly), to.Format(time.DateOnly), days), nil
+}
+
+func (a *AgendaService) GetTrash(ctx context.Context, userId int) ([]entity.Task, error) {
+	return a.repo.GetTrash(ctx, userId)
 }
 
-func (a *AgendaService) GetByDateAndStatus(ctx context.Context, userId int, status string, date time.Time, limit, offset int) ([]entity.Task, error) {
-	if status == "not_done" {
-		status = entity.StatusNotDone
+// RestoreTask takes the task out of the trash, its parent task should not be in the trash
+// and its title should not be taken by another task in the meantime.
+func (a *AgendaService) RestoreTask(ctx context.Context, id, userId int) error {
+	task, err := a.repo.GetTrashedByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrTaskIsNotInTrash
+	}
+	if err != nil {
+		return err
 	}
 
-	if status != entity.StatusDone && status != entity.StatusNotDone {
-		return nil, entity.ErrInvalidStatus
+	if task.ParentID != nil && !a.isTaskExists(ctx, *task.ParentID, userId) {
+		return entity.ErrParentTaskDoesNotExist
 	}
 
-	if limit < 0 || offset < 0 {
+	if a.isTitleTaken(ctx, task.Title, userId, task.ProjectID) {
+		return entity.ErrTaskAlreadyExist
+	}
+
+	return a.repo.Restore(ctx, id, userId)
+}
+
+// PurgeTrash permanently deletes the tasks kept in the trash longer than the retention.
+func (a *AgendaService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	return a.repo.Purge(ctx, time.Now().Add(-retention))
+}
+
+// SearchTasks finds the tasks containing all words of the query in the title or description,
+// the words are matched as prefixes ("inv" finds "invoice") so that the results can follow the user's typing.
+func (a *AgendaService) SearchTasks(ctx context.Context, userId int, query string, limit, offset int) ([]entity.TaskSearchResult, error) {
+	if limit < 1 || offset < 0 {
+		return nil, entity.ErrInvalidPaginationSizes
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil, entity.ErrInvalidSearchQuery
+	}
+
+	for i := range words {
+		words[i] += ":*"
+	}
+
+	return a.repo.Search(ctx, userId, strings.Join(words, " & "), limit, offset)
+}
+
+func (a *AgendaService) GetArchive(ctx context.Context, userId int, search string, limit, offset int) ([]entity.Task, error) {
+	if limit < 1 || offset < 0 {
 		return nil, entity.ErrInvalidPaginationSizes
 	}
 
-	return a.repo.GetByDateAndStatus(ctx, userId, status, date, limit, offset)
+	return a.repo.GetArchived(ctx, userId, strings.TrimSpace(search), limit, offset)
 }
 
-func (a *AgendaService) isTaskExists(ctx context.Context, data any, userId int) bool {
-	var task entity.Task
+// UnarchiveTask returns the archived task to the default views, it stays completed.
+func (a *AgendaService) UnarchiveTask(ctx context.Context, id, userId int) error {
+	task, err := a.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrTaskDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
 
-	switch data.(type) {
-	case string:
-		task, _ = a.repo.GetByTitleAndUserID(ctx, data.(string), userId)
-	case int:
-		task, _ = a.repo.GetByID(ctx, data.(int), userId)
+	if task.ArchivedAt == nil {
+		return entity.ErrTaskIsNotArchived
 	}
 
+	return a.repo.Unarchive(ctx, id, userId)
+}
+
+// ArchiveCompletedTasks archives at most batchSize tasks completed longer than after ago.
+func (a *AgendaService) ArchiveCompletedTasks(ctx context.Context, after time.Duration, batchSize int) (int, error) {
+	return a.repo.Archive(ctx, time.Now().Add(-after), batchSize)
+}
+
+func (a *AgendaService) isTaskExists(ctx context.Context, id, userId int) bool {
+	task, _ := a.repo.GetByID(ctx, id, userId)
+	return len(task.Title) != 0
+}
+
+// isTitleTaken reports whether the user already has a task with such title in the project
+// (or among the tasks without project, if projectId is nil).
+func (a *AgendaService) isTitleTaken(ctx context.Context, title string, userId int, projectId *int) bool {
+	task, _ := a.repo.GetByTitleAndUserID(ctx, title, userId, projectId)
 	return len(task.Title) != 0
 }
+
+// checkTransition returns the status of the task workflow the task is moved to,
+// if the workflow has it and allows the transition from the current task status.
+func (a *AgendaService) checkTransition(ctx context.Context, userId int, task entity.Task, status string) (entity.Status, error) {
+	workflow, err := workflowStatuses(ctx, a.statuses, userId, task.ProjectID)
+	if err != nil {
+		return entity.Status{}, err
+	}
+
+	target, ok := findStatus(workflow, status)
+	if !ok {
+		return entity.Status{}, entity.ErrInvalidStatus
+	}
+
+	if !isTransitionAllowed(workflow, task.Status, status) {
+		return entity.Status{}, entity.ErrStatusNotAllowed
+	}
+
+	return target, nil
+}
+
+// openBlockers returns the not completed tasks the task is blocked by.
+func (a *AgendaService) openBlockers(ctx context.Context, id, userId int) ([]entity.Task, error) {
+	blockers, err := a.dependencies.GetBlockers(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make([]entity.Task, 0, len(blockers))
+	for _, blocker := range blockers {
+		if blocker.CompletedAt == nil {
+			open = append(open, blocker)
+		}
+	}
+
+	return open, nil
+}
+
+func (a *AgendaService) isProjectExists(ctx context.Context, id, userId int) bool {
+	_, err := a.projects.GetByID(ctx, id, userId)
+	return err == nil
+}
+
+// nextOccurrence returns the next occurrence of the recurring task being completed, nil if the task does not recur
+// anymore. It is a copy of the task (without subtasks) dated by the task recurrence rule in the user's timezone.
+func (a *AgendaService) nextOccurrence(ctx context.Context, userId int, task entity.Task) (*entity.Task, error) {
+	if len(task.Recurrence) == 0 {
+		return nil, nil
+	}
+
+	rule, err := rrule.Parse(task.Recurrence)
+	if err != nil {
+		return nil, entity.ErrInvalidRecurrence
+	}
+
+	location, err := userLocation(ctx, a.users, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// a task without date recurs from the time it is completed
+	from := time.Now()
+	if task.Date != nil {
+		from = *task.Date
+	}
+
+	date, rest, ok := rule.Next(from.In(location))
+	if !ok {
+		return nil, nil
+	}
+
+	workflow, err := workflowStatuses(ctx, a.statuses, userId, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Task{
+		UserID:          userId,
+		Title:           task.Title,
+		Description:     task.Description,
+		Date:            &date,
+		Status:          initialStatus(workflow).Name,
+		Priority:        task.Priority,
+		ProjectID:       task.ProjectID,
+		ParentID:        task.ParentID,
+		Recurrence:      rest.String(),
+		Tags:            task.Tags,
+		EstimateMinutes: task.EstimateMinutes,
+		EstimatePoints:  task.EstimatePoints,
+	}, nil
+}
+
+// applyUpdate returns the task as it is going to be after the update.
+func applyUpdate(task entity.Task, update entity.TaskUpdate) entity.Task {
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Description != nil {
+		task.Description = *update.Description
+	}
+	if update.Date != nil {
+		task.Date = update.Date
+		if update.Date.IsZero() {
+			task.Date = nil
+		}
+	}
+	if update.Status != nil {
+		task.Status = *update.Status
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.Recurrence != nil {
+		task.Recurrence = *update.Recurrence
+	}
+	if update.Tags != nil {
+		task.Tags = *update.Tags
+	}
+	if update.EstimateMinutes != nil {
+		task.EstimateMinutes = *update.EstimateMinutes
+	}
+	if update.EstimatePoints != nil {
+		task.EstimatePoints = *update.EstimatePoints
+	}
+
+	return task
+}
+
+// normalizeRecurrence validates the recurrence rule and brings it to the canonical form,
+// an empty recurrence means the task does not repeat.
+func normalizeRecurrence(recurrence string) (string, error) {
+	if len(recurrence) == 0 {
+		return "", nil
+	}
+
+	rule, err := rrule.Parse(recurrence)
+	if err != nil {
+		return "", entity.ErrInvalidRecurrence
+	}
+
+	return rule.String(), nil
+}
+
+func isSameProject(lhs, rhs *int) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+
+	return *lhs == *rhs
+}
+
+// buildTaskTree nests the tasks into subtasks of their parents keeping the original order,
+// a task whose parent is not in the list stays on the top level.
+func buildTaskTree(tasks []entity.Task) []entity.Task {
+	var (
+		positions = make(map[int]int, len(tasks))
+		children  = make(map[int][]int)
+		roots     = make([]int, 0)
+	)
+
+	for i, task := range tasks {
+		positions[task.ID] = i
+	}
+
+	for i, task := range tasks {
+		if task.ParentID != nil {
+			if _, ok := positions[*task.ParentID]; ok {
+				children[*task.ParentID] = append(children[*task.ParentID], i)
+				continue
+			}
+		}
+		roots = append(roots, i)
+	}
+
+	var build func(i int) entity.Task
+	build = func(i int) entity.Task {
+		task := tasks[i]
+		for _, child := range children[task.ID] {
+			task.Subtasks = append(task.Subtasks, build(child))
+		}
+		return task
+	}
+
+	tree := make([]entity.Task, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+
+	return tree
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+// testAgendaRepo records the status changes and the listing filters, the rest of the methods are not implemented.
+type testAgendaRepo struct {
+	repository.Agenda
+	task       entity.Task
+	getErr     error
+	setStatus  string
+	listFilter entity.TaskFilter
+}
+
+func (r *testAgendaRepo) GetByID(_ context.Context, _, _ int) (entity.Task, error) {
+	return r.task, r.getErr
+}
+
+func (r *testAgendaRepo) SetStatus(_ context.Context, _, _ int, status string, _, _ bool, _ *entity.Task) error {
+	r.setStatus = status
+	return nil
+}
+
+func (r *testAgendaRepo) List(_ context.Context, _ int, filter entity.TaskFilter) (entity.TaskPage, error) {
+	r.listFilter = filter
+	return entity.TaskPage{}, nil
+}
+
+// testStatusesRepo is a user without custom statuses.
+type testStatusesRepo struct {
+	repository.Statuses
+}
+
+func (r *testStatusesRepo) GetByProjectID(_ context.Context, _ int, _ *int) ([]entity.Status, error) {
+	return nil, nil
+}
+
+func (r *testStatusesRepo) GetByUserID(_ context.Context, _ int) ([]entity.Status, error) {
+	return nil, nil
+}
+
+func TestAgendaService_SetTaskStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+		wantErr    error
+	}{
+		{name: "OK", status: entity.StatusNotDone, wantStatus: entity.StatusNotDone},
+		{name: "OK v1 alias", status: entity.StatusNotDoneAlias, wantStatus: entity.StatusNotDone},
+		{name: "ERROR unknown status", status: "not-done", wantErr: entity.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testAgendaRepo{task: entity.Task{ID: 1, UserID: 1, Status: entity.StatusDone}}
+			agenda := NewAgenda(repo, nil, nil, &testStatusesRepo{}, nil, nil)
+
+			_, err := agenda.SetTaskStatus(context.Background(), 1, 1, tt.status, false, false)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, repo.setStatus)
+			}
+		})
+	}
+}
+
+func TestAgendaService_ListTasks(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+		wantErr    error
+	}{
+		{name: "OK", status: entity.StatusDone, wantStatus: entity.StatusDone},
+		{name: "OK v1 alias", status: entity.StatusNotDoneAlias, wantStatus: entity.StatusNotDone},
+		{name: "ERROR unknown status", status: "in progress", wantErr: entity.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testAgendaRepo{}
+			agenda := NewAgenda(repo, nil, nil, &testStatusesRepo{}, nil, nil)
+
+			_, err := agenda.ListTasks(context.Background(), 1, entity.TaskFilter{Status: tt.status, Limit: 10})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, repo.listFilter.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+	"github.com/zenorachi/todo-service/pkg/logger"
+	"github.com/zenorachi/todo-service/pkg/storage"
+)
+
+// sniffLen is the number of leading bytes http.DetectContentType considers.
+const sniffLen = 512
+
+type AttachmentsService struct {
+	repo        repository.Attachments
+	agenda      repository.Agenda
+	store       storage.BlobStore
+	maxFileSize int64
+	maxUserSize int64
+}
+
+func NewAttachments(repo repository.Attachments, agenda repository.Agenda, store storage.BlobStore,
+	maxFileSize, maxUserSize int64) *AttachmentsService {
+	return &AttachmentsService{
+		repo:        repo,
+		agenda:      agenda,
+		store:       store,
+		maxFileSize: maxFileSize,
+		maxUserSize: maxUserSize,
+	}
+}
+
+// MaxFileSize returns the size limit of a single uploaded file in bytes.
+func (a *AttachmentsService) MaxFileSize() int64 {
+	return a.maxFileSize
+}
+
+// UploadAttachment stores the content of attachment.Size bytes and saves the attachment metadata,
+// the content type is detected from the content and falls back to the file extension.
+func (a *AttachmentsService) UploadAttachment(ctx context.Context, attachment entity.Attachment, content io.Reader) (int, error) {
+	if !a.isTaskExists(ctx, attachment.TaskID, attachment.UserID) {
+		return 0, entity.ErrTaskDoesNotExist
+	}
+
+	if attachment.Size > a.maxFileSize {
+		return 0, entity.ErrAttachmentTooLarge
+	}
+
+	// the quota is checked before the upload not to store the content in vain,
+	// the repository checks it once again against the concurrent uploads
+	used, err := a.repo.GetUsedSize(ctx, attachment.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if used+attachment.Size > a.maxUserSize {
+		return 0, entity.ErrStorageQuotaExceeded
+	}
+
+	key, err := newStorageKey(attachment.UserID, attachment.TaskID)
+	if err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReaderSize(io.LimitReader(content, attachment.Size), sniffLen)
+	head, err := reader.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+
+	attachment.ContentType = detectContentType(head, attachment.Filename)
+	attachment.StorageKey = key
+
+	if err = a.store.Put(ctx, key, reader, attachment.Size, attachment.ContentType); err != nil {
+		return 0, err
+	}
+
+	id, err := a.repo.Create(ctx, attachment, a.maxUserSize)
+	if err != nil {
+		a.deleteBlob(ctx, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, entity.ErrStorageQuotaExceeded
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (a *AttachmentsService) GetTaskAttachments(ctx context.Context, taskId, userId int) ([]entity.Attachment, error) {
+	if !a.isTaskExists(ctx, taskId, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return a.repo.GetByTaskID(ctx, taskId, userId)
+}
+
+// DownloadAttachment returns the attachment with its content, the caller has to close the content.
+func (a *AttachmentsService) DownloadAttachment(ctx context.Context, id, taskId, userId int) (entity.Attachment, io.ReadCloser, error) {
+	attachment, err := a.getAttachment(ctx, id, taskId, userId)
+	if err != nil {
+		return entity.Attachment{}, nil, err
+	}
+
+	content, err := a.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.Attachment{}, nil, entity.ErrAttachmentDoesNotExist
+	}
+	if err != nil {
+		return entity.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (a *AttachmentsService) DeleteAttachmentByID(ctx context.Context, id, taskId, userId int) error {
+	attachment, err := a.getAttachment(ctx, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = a.repo.DeleteByID(ctx, id, taskId, userId); err != nil {
+		return err
+	}
+
+	a.deleteBlob(ctx, attachment.StorageKey)
+	return nil
+}
+
+// PurgeOrphanedAttachments deletes the attachments (and their blobs) of the purged tasks.
+func (a *AttachmentsService) PurgeOrphanedAttachments(ctx context.Context) (int, error) {
+	keys, err := a.repo.DeleteOrphaned(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		a.deleteBlob(ctx, key)
+	}
+
+	return len(keys), nil
+}
+
+func (a *AttachmentsService) getAttachment(ctx context.Context, id, taskId, userId int) (entity.Attachment, error) {
+	attachment, err := a.repo.GetByID(ctx, id, taskId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Attachment{}, entity.ErrAttachmentDoesNotExist
+	}
+
+	return attachment, err
+}
+
+// deleteBlob only logs the failures, the metadata is already gone and the blob can be removed manually.
+func (a *AttachmentsService) deleteBlob(ctx context.Context, key string) {
+	if err := a.store.Delete(ctx, key); err != nil {
+		logger.Error("attachments", fmt.Sprintf("deleting blob %s: %s", key, err.Error()))
+	}
+}
+
+func (a *AttachmentsService) isTaskExists(ctx context.Context, id, userId int) bool {
+	_, err := a.agenda.GetByID(ctx, id, userId)
+	return err == nil
+}
+
+func newStorageKey(userId, taskId int) (string, error) {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d/%d/%s", userId, taskId, hex.EncodeToString(name)), nil
+}
+
+// detectContentType sniffs the content type, the generic results are refined by the file extension.
+func detectContentType(head []byte, filename string) string {
+	sniffed := http.DetectContentType(head)
+
+	byExtension := mime.TypeByExtension(filepath.Ext(filename))
+	if byExtension == "" {
+		return sniffed
+	}
+
+	// zip is the container of office documents, plain text covers csv, json, markdown and others
+	if sniffed == "application/octet-stream" || sniffed == "application/zip" || strings.HasPrefix(sniffed, "text/plain") {
+		return byExtension
+	}
+
+	return sniffed
+}
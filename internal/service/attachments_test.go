@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+	"github.com/zenorachi/todo-service/pkg/storage"
+)
+
+// testAttachmentsRepo is a user with used bytes of attachments, the rest of the methods are not implemented.
+type testAttachmentsRepo struct {
+	repository.Attachments
+	used int64
+}
+
+func (r *testAttachmentsRepo) GetUsedSize(_ context.Context, _ int) (int64, error) {
+	return r.used, nil
+}
+
+func (r *testAttachmentsRepo) Create(_ context.Context, _ entity.Attachment, _ int64) (int, error) {
+	return 1, nil
+}
+
+// testBlobStore records the stored keys.
+type testBlobStore struct {
+	storage.BlobStore
+	put []string
+}
+
+func (s *testBlobStore) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	s.put = append(s.put, key)
+	_, err := io.Copy(io.Discard, content)
+	return err
+}
+
+func TestAttachmentsService_UploadAttachment(t *testing.T) {
+	const (
+		maxFileSize = 1024
+		maxUserSize = 4096
+	)
+
+	tests := []struct {
+		name    string
+		used    int64
+		size    int64
+		wantPut bool
+		wantErr error
+	}{
+		{name: "OK", used: 1024, size: 1024, wantPut: true},
+		{name: "OK quota is reached exactly", used: 3072, size: 1024, wantPut: true},
+		{name: "ERROR file is too large", size: maxFileSize + 1, wantErr: entity.ErrAttachmentTooLarge},
+		{name: "ERROR quota exceeded", used: 3584, size: 1024, wantErr: entity.ErrStorageQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &testBlobStore{}
+			attachments := NewAttachments(&testAttachmentsRepo{used: tt.used}, &testAgendaRepo{}, store, maxFileSize, maxUserSize)
+
+			_, err := attachments.UploadAttachment(context.Background(),
+				entity.Attachment{TaskID: 1, UserID: 1, Filename: "notes.txt", Size: tt.size},
+				strings.NewReader(strings.Repeat("a", int(tt.size))))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPut, len(store.put) != 0)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type ChecklistsService struct {
+	repo   repository.Checklists
+	agenda repository.Agenda
+}
+
+func NewChecklists(repo repository.Checklists, agenda repository.Agenda) *ChecklistsService {
+	return &ChecklistsService{
+		repo:   repo,
+		agenda: agenda,
+	}
+}
+
+func (c *ChecklistsService) AddChecklistItem(ctx context.Context, taskId, userId int, text string) (int, error) {
+	if !c.isTaskExists(ctx, taskId, userId) {
+		return 0, entity.ErrTaskDoesNotExist
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return 0, entity.ErrInvalidInput
+	}
+
+	return c.repo.Create(ctx, entity.ChecklistItem{TaskID: taskId, Text: text})
+}
+
+func (c *ChecklistsService) GetChecklist(ctx context.Context, taskId, userId int) ([]entity.ChecklistItem, error) {
+	if !c.isTaskExists(ctx, taskId, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return c.repo.GetByTaskID(ctx, taskId)
+}
+
+func (c *ChecklistsService) UpdateChecklistItem(ctx context.Context, id, taskId, userId int, update entity.ChecklistItemUpdate) error {
+	if update.IsEmpty() {
+		return entity.ErrEmptyTaskUpdate
+	}
+
+	if update.Text != nil && strings.TrimSpace(*update.Text) == "" {
+		return entity.ErrInvalidInput
+	}
+
+	if err := c.checkItem(ctx, id, taskId, userId); err != nil {
+		return err
+	}
+
+	return c.repo.Update(ctx, id, taskId, update)
+}
+
+func (c *ChecklistsService) DeleteChecklistItem(ctx context.Context, id, taskId, userId int) error {
+	if err := c.checkItem(ctx, id, taskId, userId); err != nil {
+		return err
+	}
+
+	return c.repo.DeleteByID(ctx, id, taskId)
+}
+
+// checkItem makes sure the item exists in the checklist of the user's task.
+func (c *ChecklistsService) checkItem(ctx context.Context, id, taskId, userId int) error {
+	if !c.isTaskExists(ctx, taskId, userId) {
+		return entity.ErrTaskDoesNotExist
+	}
+
+	_, err := c.repo.GetByID(ctx, id, taskId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrChecklistItemDoesNotExist
+	}
+
+	return err
+}
+
+func (c *ChecklistsService) isTaskExists(ctx context.Context, id, userId int) bool {
+	_, err := c.agenda.GetByID(ctx, id, userId)
+	return err == nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type CommentsService struct {
+	repo   repository.Comments
+	agenda repository.Agenda
+}
+
+func NewComments(repo repository.Comments, agenda repository.Agenda) *CommentsService {
+	return &CommentsService{
+		repo:   repo,
+		agenda: agenda,
+	}
+}
+
+func (c *CommentsService) CreateComment(ctx context.Context, comment entity.Comment) (int, error) {
+	if !c.isTaskExists(ctx, comment.TaskID, comment.UserID) {
+		return 0, entity.ErrTaskDoesNotExist
+	}
+
+	if strings.TrimSpace(comment.Body) == "" {
+		return 0, entity.ErrInvalidInput
+	}
+
+	return c.repo.Create(ctx, comment)
+}
+
+func (c *CommentsService) GetTaskComments(ctx context.Context, taskId, userId int) ([]entity.Comment, error) {
+	if !c.isTaskExists(ctx, taskId, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return c.repo.GetByTaskID(ctx, taskId)
+}
+
+func (c *CommentsService) EditComment(ctx context.Context, id, taskId, userId int, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return entity.ErrInvalidInput
+	}
+
+	if err := c.checkAuthor(ctx, id, taskId, userId); err != nil {
+		return err
+	}
+
+	return c.repo.Update(ctx, id, taskId, userId, body)
+}
+
+func (c *CommentsService) DeleteCommentByID(ctx context.Context, id, taskId, userId int) error {
+	if err := c.checkAuthor(ctx, id, taskId, userId); err != nil {
+		return err
+	}
+
+	return c.repo.DeleteByID(ctx, id, taskId, userId)
+}
+
+// checkAuthor makes sure the comment exists on the user's task and was written by the user.
+func (c *CommentsService) checkAuthor(ctx context.Context, id, taskId, userId int) error {
+	if !c.isTaskExists(ctx, taskId, userId) {
+		return entity.ErrTaskDoesNotExist
+	}
+
+	comment, err := c.repo.GetByID(ctx, id, taskId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrCommentDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userId {
+		return entity.ErrNotCommentAuthor
+	}
+
+	return nil
+}
+
+func (c *CommentsService) isTaskExists(ctx context.Context, id, userId int) bool {
+	_, err := c.agenda.GetByID(ctx, id, userId)
+	return err == nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type DependenciesService struct {
+	repo   repository.Dependencies
+	agenda repository.Agenda
+}
+
+func NewDependencies(repo repository.Dependencies, agenda repository.Agenda) *DependenciesService {
+	return &DependenciesService{
+		repo:   repo,
+		agenda: agenda,
+	}
+}
+
+func (d *DependenciesService) AddDependency(ctx context.Context, taskId, blockerId, userId int) error {
+	if !d.isTaskExists(ctx, taskId, userId) {
+		return entity.ErrTaskDoesNotExist
+	}
+
+	if !d.isTaskExists(ctx, blockerId, userId) {
+		return entity.ErrBlockerDoesNotExist
+	}
+
+	if taskId == blockerId {
+		return entity.ErrDependencyCycle
+	}
+
+	created, err := d.repo.Create(ctx, taskId, blockerId)
+	if isUniqueViolation(err) {
+		return entity.ErrDependencyAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if !created {
+		return entity.ErrDependencyCycle
+	}
+
+	return nil
+}
+
+func (d *DependenciesService) GetBlockers(ctx context.Context, taskId, userId int) ([]entity.Task, error) {
+	if !d.isTaskExists(ctx, taskId, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return d.repo.GetBlockers(ctx, taskId, userId)
+}
+
+func (d *DependenciesService) RemoveDependency(ctx context.Context, taskId, blockerId, userId int) error {
+	blockers, err := d.repo.GetBlockers(ctx, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, blocker := range blockers {
+		if blocker.ID == blockerId {
+			return d.repo.Delete(ctx, taskId, blockerId, userId)
+		}
+	}
+
+	return entity.ErrDependencyDoesNotExist
+}
+
+func (d *DependenciesService) isTaskExists(ctx context.Context, id, userId int) bool {
+	_, err := d.agenda.GetByID(ctx, id, userId)
+	return err == nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type HistoryService struct {
+	repo   repository.History
+	agenda repository.Agenda
+}
+
+func NewHistory(repo repository.History, agenda repository.Agenda) *HistoryService {
+	return &HistoryService{
+		repo:   repo,
+		agenda: agenda,
+	}
+}
+
+// GetTaskHistory returns the changes of the task in chronological order, the history is kept for the tasks
+// in the trash and for the ones purged from it too.
+func (h *HistoryService) GetTaskHistory(ctx context.Context, taskId, userId int) ([]entity.HistoryEntry, error) {
+	entries, err := h.repo.GetByTaskID(ctx, taskId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 && !h.isTaskExists(ctx, taskId, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return entries, nil
+}
+
+func (h *HistoryService) isTaskExists(ctx context.Context, id, userId int) bool {
+	if _, err := h.agenda.GetByID(ctx, id, userId); err == nil {
+		return true
+	}
+
+	_, err := h.agenda.GetTrashedByID(ctx, id, userId)
+	return err == nil
+}
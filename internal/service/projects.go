@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type ProjectsService struct {
+	repo repository.Projects
+}
+
+func NewProjects(repo repository.Projects) *ProjectsService {
+	return &ProjectsService{repo: repo}
+}
+
+func (p *ProjectsService) CreateProject(ctx context.Context, project entity.Project) (int, error) {
+	id, err := p.repo.Create(ctx, project)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, entity.ErrProjectAlreadyExists
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (p *ProjectsService) GetProjectByID(ctx context.Context, id, userId int) (entity.Project, error) {
+	project, err := p.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Project{}, entity.ErrProjectDoesNotExist
+	}
+	if err != nil {
+		return entity.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (p *ProjectsService) GetUserProjects(ctx context.Context, userId int) ([]entity.Project, error) {
+	return p.repo.GetByUserID(ctx, userId)
+}
+
+func (p *ProjectsService) UpdateProject(ctx context.Context, project entity.Project) error {
+	if _, err := p.GetProjectByID(ctx, project.ID, project.UserID); err != nil {
+		return err
+	}
+
+	err := p.repo.Update(ctx, project)
+	if isUniqueViolation(err) {
+		return entity.ErrProjectAlreadyExists
+	}
+
+	return err
+}
+
+func (p *ProjectsService) DeleteProjectByID(ctx context.Context, id, userId int) error {
+	if _, err := p.GetProjectByID(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return p.repo.DeleteByID(ctx, id, userId)
+}
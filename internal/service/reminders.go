@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type RemindersService struct {
+	repo   repository.Reminders
+	agenda repository.Agenda
+}
+
+func NewReminders(repo repository.Reminders, agenda repository.Agenda) *RemindersService {
+	return &RemindersService{
+		repo:   repo,
+		agenda: agenda,
+	}
+}
+
+func (r *RemindersService) CreateReminder(ctx context.Context, reminder entity.Reminder) (int, error) {
+	if !r.isTaskExists(ctx, reminder.TaskID, reminder.UserID) {
+		return 0, entity.ErrTaskDoesNotExist
+	}
+
+	if (reminder.RemindAt == nil) == (reminder.OffsetMinutes == nil) {
+		return 0, entity.ErrInvalidReminder
+	}
+
+	if reminder.OffsetMinutes != nil && *reminder.OffsetMinutes < 0 {
+		return 0, entity.ErrInvalidReminder
+	}
+
+	return r.repo.Create(ctx, reminder)
+}
+
+func (r *RemindersService) GetTaskReminders(ctx context.Context, taskId, userId int) ([]entity.Reminder, error) {
+	if !r.isTaskExists(ctx, taskId, userId) {
+		return nil, entity.ErrTaskDoesNotExist
+	}
+
+	return r.repo.GetByTaskID(ctx, taskId, userId)
+}
+
+func (r *RemindersService) DeleteReminderByID(ctx context.Context, id, taskId, userId int) error {
+	_, err := r.repo.GetByID(ctx, id, taskId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrReminderDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.repo.DeleteByID(ctx, id, taskId, userId)
+}
+
+func (r *RemindersService) ClaimDueReminders(ctx context.Context, limit, maxAttempts int) ([]entity.DueReminder, error) {
+	return r.repo.ClaimDue(ctx, limit, maxAttempts)
+}
+
+func (r *RemindersService) ReleaseReminder(ctx context.Context, id int, backoff, maxBackoff time.Duration) error {
+	return r.repo.Release(ctx, id, backoff, maxBackoff)
+}
+
+func (r *RemindersService) isTaskExists(ctx context.Context, id, userId int) bool {
+	_, err := r.agenda.GetByID(ctx, id, userId)
+	return err == nil
+}
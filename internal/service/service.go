@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/zenorachi/todo-service/internal/entity"
@@ -9,6 +10,7 @@ import (
 	"github.com/zenorachi/todo-service/internal/repository"
 	"github.com/zenorachi/todo-service/pkg/auth"
 	"github.com/zenorachi/todo-service/pkg/hash"
+	"github.com/zenorachi/todo-service/pkg/storage"
 )
 
 type Tokens struct {
@@ -23,25 +25,133 @@ type LimitOffset struct {
 
 type (
 	Users interface {
-		SignUp(ctx context.Context, login, email, password string) (int, error)
+		SignUp(ctx context.Context, login, email, password, timezone string) (int, error)
 		SignIn(ctx context.Context, login, password string) (Tokens, error)
 		RefreshTokens(ctx context.Context, refreshToken string) (Tokens, error)
+		GetLocation(ctx context.Context, userId int) (*time.Location, error)
+		SetTimezone(ctx context.Context, userId int, timezone string) error
 	}
 
 	Agenda interface {
 		CreateTask(ctx context.Context, task entity.Task) (int, error)
 		GetTaskByID(ctx context.Context, id, userId int) (entity.Task, error)
-		SetTaskStatus(ctx context.Context, id, userId int, status string) error
-		DeleteTaskByID(ctx context.Context, id, userId int) error
+		SetTaskStatus(ctx context.Context, id, userId int, status string, cascade, force bool) ([]entity.Task, error)
+		UpdateTask(ctx context.Context, id, userId int, update entity.TaskUpdate) (entity.Task, error)
+		MoveTask(ctx context.Context, id, userId int, projectId *int) error
+		ReorderTask(ctx context.Context, id, userId int, beforeId, afterId *int) error
+		DeleteTaskByID(ctx context.Context, id, userId int, cascade bool) error
 		DeleteUserTasks(ctx context.Context, userId int) error
-		GetUserTasks(ctx context.Context, userId int) ([]entity.Task, error)
-		GetByDateAndStatus(ctx context.Context, userId int, status string, date time.Time, limit, offset int) ([]entity.Task, error)
+		GetSubtasks(ctx context.Context, id, userId int) ([]entity.Task, error)
+		GetUserTasks(ctx context.Context, userId int, filter entity.TaskFilter) ([]entity.Task, error)
+		ListTasks(ctx context.Context, userId int, filter entity.TaskFilter) (entity.TaskPage, error)
+		GetWorkloadReport(ctx context.Context, userId int, from, to time.Time) (entity.WorkloadReport, error)
+		GetTrash(ctx context.Context, userId int) ([]entity.Task, error)
+		RestoreTask(ctx context.Context, id, userId int) error
+		PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+		SearchTasks(ctx context.Context, userId int, query string, limit, offset int) ([]entity.TaskSearchResult, error)
+		GetArchive(ctx context.Context, userId int, search string, limit, offset int) ([]entity.Task, error)
+		UnarchiveTask(ctx context.Context, id, userId int) error
+		ArchiveCompletedTasks(ctx context.Context, after time.Duration, batchSize int) (int, error)
+	}
+
+	Tags interface {
+		CreateTag(ctx context.Context, tag entity.Tag) (int, error)
+		GetTagByID(ctx context.Context, id, userId int) (entity.Tag, error)
+		GetUserTags(ctx context.Context, userId int) ([]entity.Tag, error)
+		RenameTag(ctx context.Context, id, userId int, name string) error
+		DeleteTagByID(ctx context.Context, id, userId int) error
+	}
+
+	Projects interface {
+		CreateProject(ctx context.Context, project entity.Project) (int, error)
+		GetProjectByID(ctx context.Context, id, userId int) (entity.Project, error)
+		GetUserProjects(ctx context.Context, userId int) ([]entity.Project, error)
+		UpdateProject(ctx context.Context, project entity.Project) error
+		DeleteProjectByID(ctx context.Context, id, userId int) error
+	}
+
+	Reminders interface {
+		CreateReminder(ctx context.Context, reminder entity.Reminder) (int, error)
+		GetTaskReminders(ctx context.Context, taskId, userId int) ([]entity.Reminder, error)
+		DeleteReminderByID(ctx context.Context, id, taskId, userId int) error
+		ClaimDueReminders(ctx context.Context, limit, maxAttempts int) ([]entity.DueReminder, error)
+		ReleaseReminder(ctx context.Context, id int, backoff, maxBackoff time.Duration) error
+	}
+
+	Statuses interface {
+		CreateStatus(ctx context.Context, status entity.Status) (int, error)
+		GetStatuses(ctx context.Context, userId int, projectId *int) ([]entity.Status, error)
+		UpdateStatus(ctx context.Context, status entity.Status) error
+		DeleteStatusByID(ctx context.Context, id, userId int) error
+	}
+
+	Dependencies interface {
+		AddDependency(ctx context.Context, taskId, blockerId, userId int) error
+		GetBlockers(ctx context.Context, taskId, userId int) ([]entity.Task, error)
+		RemoveDependency(ctx context.Context, taskId, blockerId, userId int) error
+	}
+
+	History interface {
+		GetTaskHistory(ctx context.Context, taskId, userId int) ([]entity.HistoryEntry, error)
+	}
+
+	Comments interface {
+		CreateComment(ctx context.Context, comment entity.Comment) (int, error)
+		GetTaskComments(ctx context.Context, taskId, userId int) ([]entity.Comment, error)
+		EditComment(ctx context.Context, id, taskId, userId int, body string) error
+		DeleteCommentByID(ctx context.Context, id, taskId, userId int) error
+	}
+
+	Attachments interface {
+		UploadAttachment(ctx context.Context, attachment entity.Attachment, content io.Reader) (int, error)
+		GetTaskAttachments(ctx context.Context, taskId, userId int) ([]entity.Attachment, error)
+		DownloadAttachment(ctx context.Context, id, taskId, userId int) (entity.Attachment, io.ReadCloser, error)
+		DeleteAttachmentByID(ctx context.Context, id, taskId, userId int) error
+		PurgeOrphanedAttachments(ctx context.Context) (int, error)
+		MaxFileSize() int64
+	}
+
+	Checklists interface {
+		AddChecklistItem(ctx context.Context, taskId, userId int, text string) (int, error)
+		GetChecklist(ctx context.Context, taskId, userId int) ([]entity.ChecklistItem, error)
+		UpdateChecklistItem(ctx context.Context, id, taskId, userId int, update entity.ChecklistItemUpdate) error
+		DeleteChecklistItem(ctx context.Context, id, taskId, userId int) error
+	}
+
+	TimeEntries interface {
+		StartTimer(ctx context.Context, taskId, userId int, note string) (int, error)
+		StopTimer(ctx context.Context, userId int) (entity.TimeEntry, error)
+		AddTimeEntry(ctx context.Context, entry entity.TimeEntry) (int, error)
+		GetTimeEntries(ctx context.Context, taskId, userId int) ([]entity.TimeEntry, error)
+		UpdateTimeEntry(ctx context.Context, id, taskId, userId int, update entity.TimeEntryUpdate) error
+		DeleteTimeEntry(ctx context.Context, id, taskId, userId int) error
+		GetTimeReport(ctx context.Context, userId int, from, to time.Time) (entity.TimeReport, error)
+	}
+
+	Templates interface {
+		CreateTemplate(ctx context.Context, template entity.Template) (int, error)
+		GetTemplateByID(ctx context.Context, id, userId int) (entity.Template, error)
+		GetUserTemplates(ctx context.Context, userId int) ([]entity.Template, error)
+		UpdateTemplate(ctx context.Context, template entity.Template) error
+		DeleteTemplateByID(ctx context.Context, id, userId int) error
+		InstantiateTemplate(ctx context.Context, id, userId int, variables map[string]string, date *time.Time, projectId *int) (int, error)
 	}
 )
 
 type Services struct {
 	Users
 	Agenda
+	Tags
+	Projects
+	Reminders
+	Statuses
+	Dependencies
+	History
+	Comments
+	Attachments
+	Checklists
+	TimeEntries
+	Templates
 }
 
 type Deps struct {
@@ -50,11 +160,30 @@ type Deps struct {
 	TokenManager    auth.TokenManager
 	AccessTokenTTL  time.Duration
 	RefreshTokenTTL time.Duration
+	BlobStore       storage.BlobStore
+	MaxFileSize     int64
+	MaxUserSize     int64
 }
 
 func New(deps Deps) *Services {
+	var (
+		agenda     = NewAgenda(deps.Repos.Agenda, deps.Repos.Projects, deps.Repos.Users, deps.Repos.Statuses, deps.Repos.Dependencies, deps.Repos.Checklists)
+		checklists = NewChecklists(deps.Repos.Checklists, deps.Repos.Agenda)
+	)
+
 	return &Services{
-		Users:  NewUsers(deps.Repos.Users, deps.Hasher, deps.TokenManager, deps.AccessTokenTTL, deps.RefreshTokenTTL),
-		Agenda: NewAgenda(deps.Repos.Agenda),
+		Users:        NewUsers(deps.Repos.Users, deps.Hasher, deps.TokenManager, deps.AccessTokenTTL, deps.RefreshTokenTTL),
+		Agenda:       agenda,
+		Tags:         NewTags(deps.Repos.Tags),
+		Projects:     NewProjects(deps.Repos.Projects),
+		Reminders:    NewReminders(deps.Repos.Reminders, deps.Repos.Agenda),
+		Statuses:     NewStatuses(deps.Repos.Statuses, deps.Repos.Projects),
+		Dependencies: NewDependencies(deps.Repos.Dependencies, deps.Repos.Agenda),
+		History:      NewHistory(deps.Repos.History, deps.Repos.Agenda),
+		Comments:     NewComments(deps.Repos.Comments, deps.Repos.Agenda),
+		Attachments:  NewAttachments(deps.Repos.Attachments, deps.Repos.Agenda, deps.BlobStore, deps.MaxFileSize, deps.MaxUserSize),
+		Checklists:   checklists,
+		TimeEntries:  NewTimeEntries(deps.Repos.TimeEntries, deps.Repos.Agenda),
+		Templates:    NewTemplates(deps.Repos.Templates, agenda, checklists),
 	}
 }
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type StatusesService struct {
+	repo     repository.Statuses
+	projects repository.Projects
+}
+
+func NewStatuses(repo repository.Statuses, projects repository.Projects) *StatusesService {
+	return &StatusesService{
+		repo:     repo,
+		projects: projects,
+	}
+}
+
+func (s *StatusesService) CreateStatus(ctx context.Context, status entity.Status) (int, error) {
+	if status.ProjectID != nil {
+		if _, err := s.projects.GetByID(ctx, *status.ProjectID, status.UserID); err != nil {
+			return 0, entity.ErrProjectDoesNotExist
+		}
+	}
+
+	status.Name = strings.TrimSpace(status.Name)
+	if len(status.Name) == 0 {
+		return 0, entity.ErrInvalidStatus
+	}
+	status.Transitions = normalizeTags(status.Transitions)
+
+	id, err := s.repo.Create(ctx, status)
+	if isUniqueViolation(err) {
+		return 0, entity.ErrStatusAlreadyExists
+	}
+
+	return id, err
+}
+
+// GetStatuses returns the workflow applied to the tasks of the project (or to the tasks without project).
+func (s *StatusesService) GetStatuses(ctx context.Context, userId int, projectId *int) ([]entity.Status, error) {
+	return workflowStatuses(ctx, s.repo, userId, projectId)
+}
+
+func (s *StatusesService) UpdateStatus(ctx context.Context, status entity.Status) error {
+	current, err := s.repo.GetByID(ctx, status.ID, status.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrStatusDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
+
+	status.ProjectID = current.ProjectID
+	status.Name = strings.TrimSpace(status.Name)
+	if len(status.Name) == 0 {
+		return entity.ErrInvalidStatus
+	}
+	status.Transitions = normalizeTags(status.Transitions)
+
+	err = s.repo.Update(ctx, status, current.Name)
+	if isUniqueViolation(err) {
+		return entity.ErrStatusAlreadyExists
+	}
+
+	return err
+}
+
+func (s *StatusesService) DeleteStatusByID(ctx context.Context, id, userId int) error {
+	status, err := s.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrStatusDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
+
+	status.UserID = userId
+	used, err := s.repo.IsUsed(ctx, status)
+	if err != nil {
+		return err
+	}
+
+	if used {
+		return entity.ErrStatusInUse
+	}
+
+	return s.repo.DeleteByID(ctx, id, userId)
+}
+
+// workflowStatuses resolves the statuses applied to the tasks of the project: the project's own ones,
+// otherwise the user-wide ones. The default "not done" and "done" stay in every workflow, so the tasks
+// created before the custom statuses were configured keep valid statuses.
+func workflowStatuses(ctx context.Context, repo repository.Statuses, userId int, projectId *int) ([]entity.Status, error) {
+	if projectId != nil {
+		statuses, err := repo.GetByProjectID(ctx, userId, projectId)
+		if err != nil {
+			return nil, err
+		}
+		if len(statuses) != 0 {
+			return withDefaultStatuses(statuses), nil
+		}
+	}
+
+	statuses, err := repo.GetByProjectID(ctx, userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return withDefaultStatuses(statuses), nil
+}
+
+// withDefaultStatuses appends the default statuses missing in the configured ones after them.
+func withDefaultStatuses(statuses []entity.Status) []entity.Status {
+	position := 0
+	if len(statuses) != 0 {
+		position = statuses[len(statuses)-1].Position + 1
+	}
+
+	for _, status := range entity.DefaultStatuses() {
+		if _, ok := findStatus(statuses, status.Name); !ok {
+			status.Position = position
+			statuses = append(statuses, status)
+			position++
+		}
+	}
+
+	return statuses
+}
+
+// isKnownStatus reports whether the status is in the workflow of the project or,
+// if projectId is nil, in any of the user's workflows.
+func isKnownStatus(ctx context.Context, repo repository.Statuses, userId int, projectId *int, name string) (bool, error) {
+	var (
+		statuses []entity.Status
+		err      error
+	)
+
+	if projectId != nil {
+		statuses, err = workflowStatuses(ctx, repo, userId, projectId)
+	} else {
+		statuses, err = repo.GetByUserID(ctx, userId)
+		statuses = withDefaultStatuses(statuses)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := findStatus(statuses, name)
+	return ok, nil
+}
+
+// resolveStatusAlias returns the status the client means, the v1 "not_done" is the default "not done".
+func resolveStatusAlias(status string) string {
+	if status == entity.StatusNotDoneAlias {
+		return entity.StatusNotDone
+	}
+	return status
+}
+
+func findStatus(statuses []entity.Status, name string) (entity.Status, bool) {
+	for _, status := range statuses {
+		if status.Name == name {
+			return status, true
+		}
+	}
+	return entity.Status{}, false
+}
+
+// initialStatus returns the status new tasks start with: the first not completed one of the workflow.
+func initialStatus(statuses []entity.Status) entity.Status {
+	for _, status := range statuses {
+		if !status.Completed {
+			return status
+		}
+	}
+	return statuses[0]
+}
+
+// isTransitionAllowed reports whether the workflow allows moving a task between the statuses,
+// a status without configured transitions (or unknown to the workflow) allows any of them.
+func isTransitionAllowed(statuses []entity.Status, from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	status, ok := findStatus(statuses, from)
+	if !ok || len(status.Transitions) == 0 {
+		return true
+	}
+
+	for _, name := range status.Transitions {
+		if name == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+type TagsService struct {
+	repo repository.Tags
+}
+
+func NewTags(repo repository.Tags) *TagsService {
+	return &TagsService{repo: repo}
+}
+
+func (t *TagsService) CreateTag(ctx context.Context, tag entity.Tag) (int, error) {
+	tag.Name = strings.TrimSpace(tag.Name)
+
+	id, err := t.repo.Create(ctx, tag)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, entity.ErrTagAlreadyExists
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (t *TagsService) GetTagByID(ctx context.Context, id, userId int) (entity.Tag, error) {
+	tag, err := t.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Tag{}, entity.ErrTagDoesNotExist
+	}
+	if err != nil {
+		return entity.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func (t *TagsService) GetUserTags(ctx context.Context, userId int) ([]entity.Tag, error) {
+	return t.repo.GetByUserID(ctx, userId)
+}
+
+func (t *TagsService) RenameTag(ctx context.Context, id, userId int, name string) error {
+	if _, err := t.GetTagByID(ctx, id, userId); err != nil {
+		return err
+	}
+
+	err := t.repo.Rename(ctx, id, userId, strings.TrimSpace(name))
+	if isUniqueViolation(err) {
+		return entity.ErrTagAlreadyExists
+	}
+
+	return err
+}
+
+func (t *TagsService) DeleteTagByID(ctx context.Context, id, userId int) error {
+	if _, err := t.GetTagByID(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return t.repo.DeleteByID(ctx, id, userId)
+}
+
+// normalizeTags trims tag names and drops empty and duplicated ones, keeping the original order.
+func normalizeTags(tags []string) []string {
+	var (
+		normalized = make([]string, 0, len(tags))
+		seen       = make(map[string]struct{}, len(tags))
+	)
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if len(tag) == 0 {
+			continue
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
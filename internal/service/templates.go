@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+// maxTitleLength is the length limit of the task title column.
+const maxTitleLength = 255
+
+type TemplatesService struct {
+	repo       repository.Templates
+	agenda     Agenda
+	checklists Checklists
+}
+
+func NewTemplates(repo repository.Templates, agenda Agenda, checklists Checklists) *TemplatesService {
+	return &TemplatesService{
+		repo:       repo,
+		agenda:     agenda,
+		checklists: checklists,
+	}
+}
+
+func (t *TemplatesService) CreateTemplate(ctx context.Context, template entity.Template) (int, error) {
+	template, err := normalizeTemplate(template)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := t.repo.Create(ctx, template)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, entity.ErrTemplateAlreadyExists
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (t *TemplatesService) GetTemplateByID(ctx context.Context, id, userId int) (entity.Template, error) {
+	template, err := t.repo.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Template{}, entity.ErrTemplateDoesNotExist
+	}
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	return template, nil
+}
+
+func (t *TemplatesService) GetUserTemplates(ctx context.Context, userId int) ([]entity.Template, error) {
+	return t.repo.GetByUserID(ctx, userId)
+}
+
+func (t *TemplatesService) UpdateTemplate(ctx context.Context, template entity.Template) error {
+	if _, err := t.GetTemplateByID(ctx, template.ID, template.UserID); err != nil {
+		return err
+	}
+
+	template, err := normalizeTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	err = t.repo.Update(ctx, template)
+	if isUniqueViolation(err) {
+		return entity.ErrTemplateAlreadyExists
+	}
+
+	return err
+}
+
+func (t *TemplatesService) DeleteTemplateByID(ctx context.Context, id, userId int) error {
+	if _, err := t.GetTemplateByID(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return t.repo.DeleteByID(ctx, id, userId)
+}
+
+// InstantiateTemplate creates the task from the template going through the same validation as CreateTask,
+// the task is due at date (if any) or DueOffsetMinutes from now (the zero offset leaves it without date). Every variable of the template should have a value.
+func (t *TemplatesService) InstantiateTemplate(ctx context.Context, id, userId int, variables map[string]string,
+	date *time.Time, projectId *int) (int, error) {
+	template, err := t.GetTemplateByID(ctx, id, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	task := entity.Task{
+		UserID:      userId,
+		Title:       strings.TrimSpace(entity.RenderTemplate(template.Title, variables)),
+		Description: entity.RenderTemplate(template.Description, variables),
+		ProjectID:   projectId,
+		Tags:        template.Tags,
+	}
+
+	if missing := (entity.Template{Title: task.Title, Description: task.Description}).Variables(); len(missing) != 0 {
+		return 0, fmt.Errorf("%w: %s", entity.ErrMissingTemplateVariables, strings.Join(missing, ", "))
+	}
+
+	if len(task.Title) == 0 || utf8.RuneCountInString(task.Title) > maxTitleLength {
+		return 0, entity.ErrInvalidInput
+	}
+
+	task.Date = date
+	if date == nil && template.DueOffsetMinutes != 0 {
+		due := time.Now().Add(time.Duration(template.DueOffsetMinutes) * time.Minute)
+		task.Date = &due
+	}
+
+	taskId, err := t.agenda.CreateTask(ctx, task)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, text := range template.Checklist {
+		if _, err = t.checklists.AddChecklistItem(ctx, taskId, userId, text); err != nil {
+			return 0, err
+		}
+	}
+
+	return taskId, nil
+}
+
+// normalizeTemplate trims the template fields and drops the duplicate tags and the empty checklist items.
+func normalizeTemplate(template entity.Template) (entity.Template, error) {
+	template.Name = strings.TrimSpace(template.Name)
+	template.Title = strings.TrimSpace(template.Title)
+
+	if len(template.Name) == 0 || len(template.Title) == 0 || template.DueOffsetMinutes < 0 {
+		return entity.Template{}, entity.ErrInvalidInput
+	}
+
+	template.Tags = normalizeTags(template.Tags)
+
+	checklist := make([]string, 0, len(template.Checklist))
+	for _, text := range template.Checklist {
+		if text = strings.TrimSpace(text); len(text) != 0 {
+			checklist = append(checklist, text)
+		}
+	}
+	template.Checklist = checklist
+
+	return template, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+// maxReportRange limits the number of days in the time report.
+const maxReportRange = 366 * 24 * time.Hour
+
+type TimeEntriesService struct {
+	repo   repository.TimeEntries
+	agenda repository.Agenda
+}
+
+func NewTimeEntries(repo repository.TimeEntries, agenda repository.Agenda) *TimeEntriesService {
+	return &TimeEntriesService{
+		repo:   repo,
+		agenda: agenda,
+	}
+}
+
+// StartTimer starts tracking time on the task, a user can have only one running timer.
+func (t *TimeEntriesService) StartTimer(ctx context.Context, taskId, userId int, note string) (int, error) {
+	if err := t.checkTask(ctx, taskId, userId); err != nil {
+		return 0, err
+	}
+
+	id, err := t.repo.Create(ctx, entity.TimeEntry{
+		TaskID:    taskId,
+		UserID:    userId,
+		StartedAt: time.Now(),
+		Note:      note,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, entity.ErrTimerAlreadyRunning
+	}
+
+	return id, err
+}
+
+func (t *TimeEntriesService) StopTimer(ctx context.Context, userId int) (entity.TimeEntry, error) {
+	entry, err := t.repo.Stop(ctx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.TimeEntry{}, entity.ErrTimerIsNotRunning
+	}
+
+	return entry, err
+}
+
+// AddTimeEntry adds the time spent on the task manually, the entry should be stopped.
+func (t *TimeEntriesService) AddTimeEntry(ctx context.Context, entry entity.TimeEntry) (int, error) {
+	if entry.StoppedAt == nil || !isValidTimeSpan(entry.StartedAt, entry.StoppedAt) {
+		return 0, entity.ErrInvalidTimeEntry
+	}
+
+	if err := t.checkTask(ctx, entry.TaskID, entry.UserID); err != nil {
+		return 0, err
+	}
+
+	return t.repo.Create(ctx, entry)
+}
+
+func (t *TimeEntriesService) GetTimeEntries(ctx context.Context, taskId, userId int) ([]entity.TimeEntry, error) {
+	if err := t.checkTask(ctx, taskId, userId); err != nil {
+		return nil, err
+	}
+
+	return t.repo.GetByTaskID(ctx, taskId, userId)
+}
+
+func (t *TimeEntriesService) UpdateTimeEntry(ctx context.Context, id, taskId, userId int, update entity.TimeEntryUpdate) error {
+	if update.IsEmpty() {
+		return entity.ErrEmptyTaskUpdate
+	}
+
+	entry, err := t.getEntry(ctx, id, taskId, userId)
+	if err != nil {
+		return err
+	}
+
+	if update.StartedAt != nil {
+		entry.StartedAt = *update.StartedAt
+	}
+	if update.StoppedAt != nil {
+		entry.StoppedAt = update.StoppedAt
+	}
+
+	if !isValidTimeSpan(entry.StartedAt, entry.StoppedAt) {
+		return entity.ErrInvalidTimeEntry
+	}
+
+	return t.repo.Update(ctx, id, taskId, userId, update)
+}
+
+func (t *TimeEntriesService) DeleteTimeEntry(ctx context.Context, id, taskId, userId int) error {
+	if _, err := t.getEntry(ctx, id, taskId, userId); err != nil {
+		return err
+	}
+
+	return t.repo.DeleteByID(ctx, id, taskId, userId)
+}
+
+// GetTimeReport sums the time tracked by the user up per day and per task,
+// from and to are the starts of the first and the last day of the report in the user's location.
+func (t *TimeEntriesService) GetTimeReport(ctx context.Context, userId int, from, to time.Time) (entity.TimeReport, error) {
+	if to.Before(from) || to.Sub(from) > maxReportRange {
+		return entity.TimeReport{}, entity.ErrInvalidDateRange
+	}
+
+	rows, err := t.repo.GetReport(ctx, userId, from, to)
+	if err != nil {
+		return entity.TimeReport{}, err
+	}
+
+	return entity.NewTimeReport(from.Format(time.DateOnly), to.Format(time.DateOnly), rows), nil
+}
+
+// getEntry returns the entry of the user's task.
+func (t *TimeEntriesService) getEntry(ctx context.Context, id, taskId, userId int) (entity.TimeEntry, error) {
+	if err := t.checkTask(ctx, taskId, userId); err != nil {
+		return entity.TimeEntry{}, err
+	}
+
+	entry, err := t.repo.GetByID(ctx, id, taskId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.TimeEntry{}, entity.ErrTimeEntryDoesNotExist
+	}
+
+	return entry, err
+}
+
+// checkTask returns entity.ErrTaskDoesNotExist if the user has no such task.
+func (t *TimeEntriesService) checkTask(ctx context.Context, id, userId int) error {
+	_, err := t.agenda.GetByID(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.ErrTaskDoesNotExist
+	}
+
+	return err
+}
+
+// isValidTimeSpan checks the span is not reversed and is not in the future, a running timer has no end.
+func isValidTimeSpan(startedAt time.Time, stoppedAt *time.Time) bool {
+	now := time.Now()
+	if stoppedAt == nil {
+		return !startedAt.After(now)
+	}
+
+	return !startedAt.After(*stoppedAt) && !stoppedAt.After(now)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/repository"
+)
+
+// testTimeEntriesRepo is a task with one time entry, the rest of the methods are not implemented.
+type testTimeEntriesRepo struct {
+	repository.TimeEntries
+}
+
+func (r *testTimeEntriesRepo) GetByTaskID(_ context.Context, taskId, userId int) ([]entity.TimeEntry, error) {
+	return []entity.TimeEntry{{ID: 1, TaskID: taskId, UserID: userId}}, nil
+}
+
+func TestTimeEntriesService_GetTimeEntries(t *testing.T) {
+	testErr := errors.New("test error")
+
+	tests := []struct {
+		name    string
+		getErr  error
+		wantErr error
+	}{
+		{name: "OK"},
+		{name: "ERROR task does not exist", getErr: sql.ErrNoRows, wantErr: entity.ErrTaskDoesNotExist},
+		{name: "ERROR database", getErr: testErr, wantErr: testErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeEntries := NewTimeEntries(&testTimeEntriesRepo{}, &testAgendaRepo{getErr: tt.getErr})
+
+			entries, err := timeEntries.GetTimeEntries(context.Background(), 1, 1)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, entries, 1)
+			}
+		})
+	}
+}
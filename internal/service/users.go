@@ -32,11 +32,19 @@ func NewUsers(repo repository.Users, hasher hash.PasswordHasher, tokenManager au
 	}
 }
 
-func (u *UserService) SignUp(ctx context.Context, login, email, password string) (int, error) {
+func (u *UserService) SignUp(ctx context.Context, login, email, password, timezone string) (int, error) {
 	if u.isUserExists(ctx, login) {
 		return 0, entity.ErrUserAlreadyExists
 	}
 
+	if len(timezone) == 0 {
+		timezone = time.UTC.String()
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return 0, entity.ErrInvalidTimezone
+	}
+
 	hashedPassword, err := u.hasher.Hash(password)
 	if err != nil {
 		return 0, err
@@ -46,6 +54,7 @@ func (u *UserService) SignUp(ctx context.Context, login, email, password string)
 		Login:    login,
 		Email:    email,
 		Password: hashedPassword,
+		Timezone: timezone,
 	})
 	if err != nil {
 		if u.isEmailExists(err) {
@@ -88,6 +97,18 @@ func (u *UserService) RefreshTokens(ctx context.Context, refreshToken string) (T
 	return u.createSession(ctx, user.ID)
 }
 
+func (u *UserService) GetLocation(ctx context.Context, userId int) (*time.Location, error) {
+	return userLocation(ctx, u.repo, userId)
+}
+
+func (u *UserService) SetTimezone(ctx context.Context, userId int, timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return entity.ErrInvalidTimezone
+	}
+
+	return u.repo.SetTimezone(ctx, userId, timezone)
+}
+
 func (u *UserService) createSession(ctx context.Context, userId int) (Tokens, error) {
 	var (
 		tokens Tokens
@@ -125,3 +146,21 @@ func (u *UserService) isEmailExists(err error) bool {
 
 	return false
 }
+
+// userLocation loads the user's timezone, dates without offset and calendar days are evaluated in it.
+func userLocation(ctx context.Context, users repository.Users, userId int) (*time.Location, error) {
+	timezone, err := users.GetTimezone(ctx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrUserDoesNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, entity.ErrInvalidTimezone
+	}
+
+	return location, nil
+}
@@ -13,26 +13,47 @@ import (
 
 const dateFormat = "2006-Jan-02"
 
+// localDateFormats are the accepted layouts of dates without offset, they are interpreted in the user's timezone.
+var localDateFormats = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02", dateFormat}
+
 func (h *Handler) initAgendaRoutes(api *gin.RouterGroup) {
 	agenda := api.Group("/agenda", h.userIdentity)
 	{
 		agenda.POST("/create", h.createTask)
 		agenda.GET("/:task_id", h.getTaskByID)
+		agenda.PATCH("/:task_id", h.updateTask)
+		agenda.PUT("/:task_id/project", h.moveTask)
+		agenda.PUT("/:task_id/move", h.reorderTask)
+		agenda.GET("/:task_id/subtasks", h.getSubtasks)
 		agenda.PUT("/set_status", h.setTaskStatus)
 		agenda.DELETE("/delete_by_id", h.deleteTaskByID)
 		agenda.DELETE("/delete_all", h.deleteUserTasks)
+		agenda.GET("/trash", h.getTrash)
+		agenda.POST("/:task_id/restore", h.restoreTask)
+		agenda.GET("/archive", h.getArchive)
+		agenda.GET("/search", h.searchTasks)
+		agenda.POST("/:task_id/unarchive", h.unarchiveTask)
 		agenda.GET("/get_all", h.getUserTasks)
+		agenda.GET("/list", h.listTasks)
 		agenda.GET("/get_by_date", h.getTasksByDataAndStatus)
+		agenda.GET("/workload", h.getWorkloadReport)
 	}
 }
 
 /* -- CREATE TASK -- */
 
 type createTaskInput struct {
-	Title       string `json:"title"    binding:"required,min=2,max=64"`
-	Description string `json:"description"`
-	Date        string `json:"date" binding:"required,min=6,max=64"`
-	Status      string `json:"status"`
+	Title           string   `json:"title"    binding:"required,min=2,max=64"`
+	Description     string   `json:"description"`
+	Date            string   `json:"date" binding:"omitempty,min=6,max=64"`
+	Status          string   `json:"status"`
+	Priority        string   `json:"priority"`
+	ProjectID       *int     `json:"project_id"`
+	ParentID        *int     `json:"parent_id"`
+	Recurrence      string   `json:"recurrence" binding:"max=255"`
+	Tags            []string `json:"tags" binding:"omitempty,max=16,dive,min=1,max=64"`
+	EstimateMinutes int      `json:"estimate_minutes" binding:"min=0"`
+	EstimatePoints  int      `json:"estimate_points" binding:"min=0"`
 }
 
 type createTaskResponse struct {
@@ -41,7 +62,7 @@ type createTaskResponse struct {
 
 // @Summary Create task
 // @Security Bearer
-// @Description create task
+// @Description create task (recurrence is an iCalendar RRULE, the next occurrence is created when the task is done; estimates are in minutes or story points, zero means not estimated)
 // @Tags agenda
 // @Accept json
 // @Produce json
@@ -58,24 +79,45 @@ func (h *Handler) createTask(c *gin.Context) {
 		return
 	}
 
-	date, err := time.Parse(dateFormat, input.Date)
-	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidData.Error())
-		return
+	var date *time.Time
+	if len(input.Date) != 0 {
+		location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		parsed, err := parseDate(input.Date, location)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		date = &parsed
 	}
 
 	id, err := h.services.Agenda.CreateTask(c, entity.Task{
-		UserID:      c.GetInt(userCtx),
-		Title:       input.Title,
-		Description: input.Description,
-		Date:        date,
-		Status:      input.Status,
+		UserID:          c.GetInt(userCtx),
+		Title:           input.Title,
+		Description:     input.Description,
+		Date:            date,
+		Status:          input.Status,
+		Priority:        input.Priority,
+		ProjectID:       input.ProjectID,
+		ParentID:        input.ParentID,
+		Recurrence:      input.Recurrence,
+		Tags:            input.Tags,
+		EstimateMinutes: input.EstimateMinutes,
+		EstimatePoints:  input.EstimatePoints,
 	})
 
 	if err != nil {
 		if errors.Is(err, entity.ErrTaskAlreadyExist) {
 			newErrorResponse(c, http.StatusConflict, err.Error())
-		} else if errors.Is(err, entity.ErrInvalidStatus) {
+		} else if errors.Is(err, entity.ErrInvalidStatus) ||
+			errors.Is(err, entity.ErrInvalidPriority) ||
+			errors.Is(err, entity.ErrProjectDoesNotExist) ||
+			errors.Is(err, entity.ErrParentTaskDoesNotExist) ||
+			errors.Is(err, entity.ErrInvalidRecurrence) {
 			newErrorResponse(c, http.StatusBadRequest, err.Error())
 		} else {
 			newErrorResponse(c, http.StatusInternalServerError, err.Error())
@@ -103,8 +145,7 @@ type getTaskByIDResponse struct {
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/agenda/:task_id [get]
 func (h *Handler) getTaskByID(c *gin.Context) {
-	paramId := strings.Trim(c.Param("task_id"), "/")
-	id, err := strconv.Atoi(paramId)
+	id, err := getIdParam(c, "task_id")
 	if err != nil {
 		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
 		return
@@ -123,21 +164,213 @@ func (h *Handler) getTaskByID(c *gin.Context) {
 	newResponse(c, http.StatusOK, getTaskByIDResponse{Task: task})
 }
 
+/* --- UPDATE TASK --- */
+
+type updateTaskInput struct {
+	Title           *string   `json:"title" binding:"omitempty,min=2,max=64"`
+	Description     *string   `json:"description"`
+	Date            *string   `json:"date" binding:"omitempty,min=6,max=64"`
+	Status          *string   `json:"status"`
+	Priority        *string   `json:"priority"`
+	Recurrence      *string   `json:"recurrence" binding:"omitempty,max=255"`
+	Tags            *[]string `json:"tags" binding:"omitempty,max=16,dive,min=1,max=64"`
+	EstimateMinutes *int      `json:"estimate_minutes" binding:"omitempty,min=0"`
+	EstimatePoints  *int      `json:"estimate_points" binding:"omitempty,min=0"`
+}
+
+type updateTaskResponse struct {
+	Task entity.Task `json:"task"`
+}
+
+// @Summary Update Task
+// @Security Bearer
+// @Description partial updating of task by id (only passed fields will be changed, empty date removes it, empty recurrence stops repeating, zero estimate removes it)
+// @Tags agenda
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param input body updateTaskInput true "input"
+// @Success 200 {object} updateTaskResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id [patch]
+func (h *Handler) updateTask(c *gin.Context) {
+	id, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input updateTaskInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	update := entity.TaskUpdate{
+		Title:           input.Title,
+		Description:     input.Description,
+		Status:          input.Status,
+		Priority:        input.Priority,
+		Recurrence:      input.Recurrence,
+		Tags:            input.Tags,
+		EstimateMinutes: input.EstimateMinutes,
+		EstimatePoints:  input.EstimatePoints,
+	}
+
+	if input.Date != nil && len(*input.Date) == 0 {
+		update.Date = &time.Time{}
+	} else if input.Date != nil {
+		location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		date, err := parseDate(*input.Date, location)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		update.Date = &date
+	}
+
+	task, err := h.services.Agenda.UpdateTask(c, id, c.GetInt(userCtx), update)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskAlreadyExist) || errors.Is(err, entity.ErrStatusNotAllowed) ||
+			errors.Is(err, entity.ErrTaskIsBlocked) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTaskDoesNotExist) ||
+			errors.Is(err, entity.ErrInvalidStatus) ||
+			errors.Is(err, entity.ErrInvalidPriority) ||
+			errors.Is(err, entity.ErrInvalidRecurrence) ||
+			errors.Is(err, entity.ErrEmptyTaskUpdate) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, updateTaskResponse{Task: task})
+}
+
+/* --- MOVE TASK TO PROJECT --- */
+
+type moveTaskInput struct {
+	ProjectID *int `json:"project_id"`
+}
+
+// @Summary Move Task To Project
+// @Security Bearer
+// @Description moving task to another project (null project_id moves task out of any project)
+// @Tags agenda
+// @Accept json
+// @Param task_id path int true "Task ID"
+// @Param input body moveTaskInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/project [put]
+func (h *Handler) moveTask(c *gin.Context) {
+	id, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input moveTaskInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Agenda.MoveTask(c, id, c.GetInt(userCtx), input.ProjectID)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskAlreadyExist) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrProjectDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- REORDER TASK --- */
+
+type reorderTaskInput struct {
+	BeforeID *int `json:"before_id"`
+	AfterID  *int `json:"after_id"`
+}
+
+// @Summary Reorder Task
+// @Security Bearer
+// @Description moving task right before or right after another task in the manual order of tasks (exactly one of before_id and after_id should be set)
+// @Tags agenda
+// @Accept json
+// @Param task_id path int true "Task ID"
+// @Param input body reorderTaskInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/move [put]
+func (h *Handler) reorderTask(c *gin.Context) {
+	id, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input reorderTaskInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Agenda.ReorderTask(c, id, c.GetInt(userCtx), input.BeforeID, input.AfterID)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidMove) || errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
 /* --- SET TASK STATUS --- */
 
 type setTaskStatusInput struct {
-	ID     int    `json:"task_id" binding:"required"`
-	Status string `json:"status" binding:"required"`
+	ID      int    `json:"task_id" binding:"required"`
+	Status  string `json:"status" binding:"required"`
+	Cascade bool   `json:"cascade"`
+	Force   bool   `json:"force"`
+}
+
+type setTaskStatusResponse struct {
+	Warning   string        `json:"warning"`
+	BlockedBy []entity.Task `json:"blocked_by"`
 }
 
 // @Summary Update Task Status
 // @Security Bearer
-// @Description updating task status by id
+// @Description updating task status by id, the status should be one of the task workflow and allowed to be moved to (with cascade, completing the task also completes all its not completed subtasks with the completed status of their own workflow; completing a recurring task creates its next occurrence, unless another not completed task has its title; a task blocked by not completed tasks is completed only with force, the blockers are returned as a warning)
 // @Tags agenda
 // @Accept json
+// @Produce json
 // @Param input body setTaskStatusInput true "input"
+// @Success 200 {object} setTaskStatusResponse
 // @Success 204 "No Content"
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/agenda/set_status [put]
 func (h *Handler) setTaskStatus(c *gin.Context) {
@@ -147,9 +380,11 @@ func (h *Handler) setTaskStatus(c *gin.Context) {
 		return
 	}
 
-	err := h.services.Agenda.SetTaskStatus(c, input.ID, c.GetInt(userCtx), input.Status)
+	blockers, err := h.services.Agenda.SetTaskStatus(c, input.ID, c.GetInt(userCtx), input.Status, input.Cascade, input.Force)
 	if err != nil {
-		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrInvalidStatus) {
+		if errors.Is(err, entity.ErrStatusNotAllowed) || errors.Is(err, entity.ErrTaskIsBlocked) || errors.Is(err, entity.ErrTaskAlreadyExist) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrInvalidStatus) {
 			newErrorResponse(c, http.StatusBadRequest, err.Error())
 		} else {
 			newErrorResponse(c, http.StatusInternalServerError, err.Error())
@@ -157,23 +392,33 @@ func (h *Handler) setTaskStatus(c *gin.Context) {
 		return
 	}
 
+	if len(blockers) != 0 {
+		newResponse(c, http.StatusOK, setTaskStatusResponse{
+			Warning:   "task is completed while blocked by not completed tasks",
+			BlockedBy: blockers,
+		})
+		return
+	}
+
 	newResponse(c, http.StatusNoContent, nil)
 }
 
 /* --- DELETE TASK BY ID --- */
 
 type deleteTaskByIdInput struct {
-	ID int `json:"task_id" binding:"required"`
+	ID      int  `json:"task_id" binding:"required"`
+	Cascade bool `json:"cascade"`
 }
 
 // @Summary Delete Task By ID
 // @Security Bearer
-// @Description deleting task by id
+// @Description moving task by id to the trash (a task with subtasks is deleted only with cascade, together with the subtasks)
 // @Tags agenda
 // @Accept json
 // @Param input body deleteTaskByIdInput true "input"
 // @Success 204 "No Content"
 // @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/agenda/delete_by_id [delete]
 func (h *Handler) deleteTaskByID(c *gin.Context) {
@@ -183,10 +428,12 @@ func (h *Handler) deleteTaskByID(c *gin.Context) {
 		return
 	}
 
-	err := h.services.Agenda.DeleteTaskByID(c, input.ID, c.GetInt(userCtx))
+	err := h.services.Agenda.DeleteTaskByID(c, input.ID, c.GetInt(userCtx), input.Cascade)
 	if err != nil {
 		if errors.Is(err, entity.ErrTaskDoesNotExist) {
 			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, entity.ErrTaskHasSubtasks) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
 		} else {
 			newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		}
@@ -196,11 +443,47 @@ func (h *Handler) deleteTaskByID(c *gin.Context) {
 	newResponse(c, http.StatusNoContent, nil)
 }
 
+/* --- GET SUBTASKS --- */
+
+type getSubtasksResponse struct {
+	Subtasks []entity.Task `json:"subtasks"`
+}
+
+// @Summary Get Subtasks
+// @Security Bearer
+// @Description getting direct subtasks of the task
+// @Tags agenda
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getSubtasksResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/subtasks [get]
+func (h *Handler) getSubtasks(c *gin.Context) {
+	id, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	subtasks, err := h.services.Agenda.GetSubtasks(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getSubtasksResponse{Subtasks: subtasks})
+}
+
 /* --- DELETE ALL USER TASKS --- */
 
 // @Summary Delete All User Tasks
 // @Security Bearer
-// @Description deleting all user tasks by user id
+// @Description moving all user tasks to the trash
 // @Tags agenda
 // @Success 204 "No Content"
 // @Failure 500 {object} errorResponse
@@ -215,6 +498,183 @@ func (h *Handler) deleteUserTasks(c *gin.Context) {
 	newResponse(c, http.StatusNoContent, nil)
 }
 
+/* --- GET TRASH --- */
+
+type getTrashResponse struct {
+	Tasks []entity.Task `json:"tasks"`
+}
+
+// @Summary Get Trash
+// @Security Bearer
+// @Description getting all user tasks from the trash (recently deleted first), they are purged after the retention period
+// @Tags agenda
+// @Produce json
+// @Success 200 {object} getTrashResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/trash [get]
+func (h *Handler) getTrash(c *gin.Context) {
+	tasks, err := h.services.Agenda.GetTrash(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTrashResponse{Tasks: tasks})
+}
+
+/* --- RESTORE TASK --- */
+
+// @Summary Restore Task
+// @Security Bearer
+// @Description restoring task from the trash together with the subtasks deleted along with it
+// @Tags agenda
+// @Param task_id path int true "Task ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/restore [post]
+func (h *Handler) restoreTask(c *gin.Context) {
+	id, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Agenda.RestoreTask(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskAlreadyExist) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTaskIsNotInTrash) || errors.Is(err, entity.ErrParentTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- SEARCH TASKS --- */
+
+type searchTasksResponse struct {
+	Results []entity.TaskSearchResult `json:"results"`
+}
+
+// @Summary Search Tasks
+// @Security Bearer
+// @Description full-text search of user tasks (including archived ones) by title and description, every word of the query is matched as a prefix, the best matches first
+// @Tags agenda
+// @Produce json
+// @Param q query string true "Search query"
+// @Param limit query int false "Limit, default - 20"
+// @Param offset query int false "Offset, default - 0"
+// @Success 200 {object} searchTasksResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/search [get]
+func (h *Handler) searchTasks(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidPaginationSizes.Error())
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidPaginationSizes.Error())
+		return
+	}
+
+	results, err := h.services.Agenda.SearchTasks(c, c.GetInt(userCtx), c.Query("q"), limit, offset)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidSearchQuery) || errors.Is(err, entity.ErrInvalidPaginationSizes) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, searchTasksResponse{Results: results})
+}
+
+/* --- GET ARCHIVE --- */
+
+type getArchiveResponse struct {
+	Tasks []entity.Task `json:"tasks"`
+}
+
+// @Summary Get Archive
+// @Security Bearer
+// @Description getting user tasks archived automatically some time after completion (recently archived first), they are hidden from the other listings
+// @Tags agenda
+// @Produce json
+// @Param search query string false "Text to look for in the title or description"
+// @Param limit query int false "Limit, default - 20"
+// @Param offset query int false "Offset, default - 0"
+// @Success 200 {object} getArchiveResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/archive [get]
+func (h *Handler) getArchive(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidPaginationSizes.Error())
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidPaginationSizes.Error())
+		return
+	}
+
+	tasks, err := h.services.Agenda.GetArchive(c, c.GetInt(userCtx), c.Query("search"), limit, offset)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidPaginationSizes) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getArchiveResponse{Tasks: tasks})
+}
+
+/* --- UNARCHIVE TASK --- */
+
+// @Summary Unarchive Task
+// @Security Bearer
+// @Description returning archived task to the other listings, it stays completed (reopening a task unarchives it as well)
+// @Tags agenda
+// @Param task_id path int true "Task ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/unarchive [post]
+func (h *Handler) unarchiveTask(c *gin.Context) {
+	id, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Agenda.UnarchiveTask(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrTaskIsNotArchived) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
 /* --- GET ALL USER TASKS --- */
 
 type getAllUserTasksResponse struct {
@@ -223,22 +683,110 @@ type getAllUserTasksResponse struct {
 
 // @Summary Get All User Tasks
 // @Security Bearer
-// @Description getting all user tasks by user id
+// @Description getting all user tasks by user id (in the manual rank order or by the sort fields, also support filtering by priority or with a filter query)
 // @Tags agenda
 // @Produce json
+// @Param q query string false "Filter query, e.g. `status:done priority>=high due<2024-06-01 -tag:home report` (fields: status, priority, due, tag, project, title, is; OR, -negation and parentheses are supported)"
+// @Param priority query string false "Priority (none, low, medium, high, urgent)"
+// @Param tag query []string false "Tags (repeatable or comma-separated)" collectionFormat(multi)
+// @Param tag_mode query string false "Tags matching mode (any, all), default - any"
+// @Param project_id query int false "Project ID (0 - tasks without project)"
+// @Param actionable query bool false "Only tasks whose blockers are all completed"
+// @Param tree query bool false "Nest subtasks into their parents"
+// @Param sort query string false "Comma-separated sort fields (rank, id, title, date, status, priority, completed_at, estimate_minutes, estimate_points), a leading minus sorts descending, e.g. -priority,date,title. Default - rank"
 // @Success 200 {object} getAllUserTasksResponse
+// @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/agenda/get_all [get]
 func (h *Handler) getUserTasks(c *gin.Context) {
-	tasks, err := h.services.Agenda.GetUserTasks(c, c.GetInt(userCtx))
+	projectId, err := getProjectQuery(c)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (project_id)")
+		return
+	}
+
+	tags, matchAll := getTagsQuery(c)
+
+	tasks, err := h.services.Agenda.GetUserTasks(c, c.GetInt(userCtx), entity.TaskFilter{
+		Priority:     c.Query("priority"),
+		ProjectID:    projectId,
+		Tags:         tags,
+		TagsMatchAll: matchAll,
+		Actionable:   c.Query("actionable") == "true",
+		AsTree:       c.Query("tree") == "true",
+		Query:        c.Query("q"),
+		Sort:         c.Query("sort"),
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidPriority) || errors.Is(err, entity.ErrInvalidFilterQuery) || errors.Is(err, entity.ErrInvalidSort) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
 	newResponse(c, http.StatusOK, getAllUserTasksResponse{Tasks: tasks})
 }
 
+/* --- LIST TASKS --- */
+
+type listTasksResponse struct {
+	Tasks      []entity.Task `json:"tasks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// @Summary List Tasks
+// @Security Bearer
+// @Description listing user tasks by date (a calendar day in the user's timezone), date range, view, status, priority or filter query in the sort order, page by page. The next page is requested with the next_cursor of the previous one (with the same sort), it is absent on the last page. The number of the tasks on all pages is returned in the X-Total-Count header
+// @Tags agenda
+// @Produce json
+// @Param q query string false "Filter query, e.g. `status:done priority>=high due<2024-06-01 -tag:home report` (fields: status, priority, due, tag, project, title, is; OR, -negation and parentheses are supported), with a query the completed tasks are not excluded by default"
+// @Param date query string false "Date (2006-01-02)"
+// @Param from query string false "First day of the date range (2006-01-02), ignored if the date is set"
+// @Param to query string false "Last day of the date range (2006-01-02), default - from. Without from the range is open-ended"
+// @Param view query string false "View (overdue - not completed tasks due before now, upcoming - tasks due in the next days, no_date - tasks without date)"
+// @Param days query int false "Number of days of the upcoming view (today included), default - 7"
+// @Param status query string false "Status (one of the workflow statuses, not_done is accepted for not done), default - all not completed tasks"
+// @Param priority query string false "Priority (none, low, medium, high, urgent)"
+// @Param tag query []string false "Tags (repeatable or comma-separated)" collectionFormat(multi)
+// @Param tag_mode query string false "Tags matching mode (any, all), default - any"
+// @Param project_id query int false "Project ID (0 - tasks without project)"
+// @Param actionable query bool false "Only tasks whose blockers are all completed"
+// @Param tree query bool false "Nest subtasks into their parents"
+// @Param limit query int false "Page size, default - 20"
+// @Param sort query string false "Comma-separated sort fields (rank, id, title, date, status, priority, completed_at, estimate_minutes, estimate_points), a leading minus sorts descending, e.g. -priority,date,title. Default - rank"
+// @Param cursor query string false "Next cursor of the previous page"
+// @Success 200 {object} listTasksResponse
+// @Header 200 {int} X-Total-Count "Number of the tasks on all pages"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/list [get]
+func (h *Handler) listTasks(c *gin.Context) {
+	filter, ok := h.getListFilter(c, c.Query("date"))
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidPaginationSizes.Error())
+		return
+	}
+	filter.Limit = limit
+
+	if cursor := c.Query("cursor"); len(cursor) != 0 {
+		after, err := entity.DecodeTaskCursor(cursor)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.After = &after
+	}
+
+	h.listTasksPage(c, filter)
+}
+
 /* --- GET ALL USER TASKS BY DATE --- */
 
 type getAllUserTasksByDataInput struct {
@@ -249,15 +797,37 @@ type getAllUserTasksByDataInput struct {
 
 // @Summary Get All User Tasks
 // @Security Bearer
-// @Description getting all user tasks by user id and date or status (also support pagination)
+// @Description getting all user tasks by user id and date (a calendar day in the user's timezone), status or priority (also support pagination). Kept for v1 clients sending the date and the page size (offset) in the body, a request without body is handled as the task listing
 // @Tags agenda
 // @Accept json
 // @Produce json
-// @Param input body getAllUserTasksByDataInput true "input"
-// @Success 200 {object} getAllUserTasksResponse
+// @Param q query string false "Filter query, e.g. `status:done priority>=high due<2024-06-01 -tag:home report` (fields: status, priority, due, tag, project, title, is; OR, -negation and parentheses are supported), with a query the completed tasks are not excluded by default"
+// @Param status query string false "Status (one of the workflow statuses, not_done is accepted for not done), default - all not completed tasks"
+// @Param priority query string false "Priority (none, low, medium, high, urgent)"
+// @Param tag query []string false "Tags (repeatable or comma-separated)" collectionFormat(multi)
+// @Param tag_mode query string false "Tags matching mode (any, all), default - any"
+// @Param project_id query int false "Project ID (0 - tasks without project)"
+// @Param actionable query bool false "Only tasks whose blockers are all completed"
+// @Param tree query bool false "Nest subtasks into their parents"
+// @Param from query string false "First day of the date range (2006-01-02), ignored if the date is set"
+// @Param to query string false "Last day of the date range (2006-01-02), default - from. Without from the range is open-ended"
+// @Param view query string false "View (overdue - not completed tasks due before now, upcoming - tasks due in the next days, no_date - tasks without date)"
+// @Param days query int false "Number of days of the upcoming view (today included), default - 7"
+// @Param page query int false "Page"
+// @Param sort query string false "Comma-separated sort fields (rank, id, title, date, status, priority, completed_at, estimate_minutes, estimate_points), a leading minus sorts descending, e.g. -priority,date,title. Default - rank"
+// @Param input body getAllUserTasksByDataInput false "input"
+// @Success 200 {object} listTasksResponse
+// @Header 200 {int} X-Total-Count "Number of the tasks on all pages"
+// @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
+// @Deprecated
 // @Router /api/v1/agenda/get_by_date [get]
 func (h *Handler) getTasksByDataAndStatus(c *gin.Context) {
+	if c.Request.ContentLength == 0 {
+		h.listTasks(c)
+		return
+	}
+
 	var input getAllUserTasksByDataInput
 	if err := c.BindJSON(&input); err != nil {
 		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
@@ -270,24 +840,209 @@ func (h *Handler) getTasksByDataAndStatus(c *gin.Context) {
 		return
 	}
 
-	var (
-		date   = time.Time{}
-		status = c.DefaultQuery("status", "not done")
-	)
+	filter, ok := h.getListFilter(c, input.Date)
+	if !ok {
+		return
+	}
+	filter.Limit, filter.Offset = input.Limit, (page-1)*input.Offset
 
-	if len(input.Date) != 0 {
-		date, err = time.Parse(dateFormat, input.Date)
+	h.listTasksPage(c, filter)
+}
+
+// getListFilter reads the task listing parameters except the pagination from the query,
+// the error response is already sent if it fails.
+func (h *Handler) getListFilter(c *gin.Context, date string) (entity.TaskFilter, bool) {
+	projectId, err := getProjectQuery(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (project_id)")
+		return entity.TaskFilter{}, false
+	}
+
+	tags, matchAll := getTagsQuery(c)
+
+	filter := entity.TaskFilter{
+		Status:       c.Query("status"),
+		Priority:     c.Query("priority"),
+		ProjectID:    projectId,
+		Tags:         tags,
+		TagsMatchAll: matchAll,
+		Actionable:   c.Query("actionable") == "true",
+		AsTree:       c.Query("tree") == "true",
+		View:         c.Query("view"),
+		Query:        c.Query("q"),
+		Sort:         c.Query("sort"),
+	}
+
+	if days, ok := c.GetQuery("days"); ok {
+		if filter.Days, err = strconv.Atoi(days); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, "invalid parameter (days)")
+			return entity.TaskFilter{}, false
+		}
+	}
+
+	if len(date) != 0 || len(c.Query("from")) != 0 || len(c.Query("to")) != 0 {
+		location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
 		if err != nil {
-			newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidData.Error())
-			return
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return entity.TaskFilter{}, false
 		}
+
+		var from, to time.Time
+		switch {
+		case len(date) != 0:
+			from, err = parseDay(date, location)
+			to = from
+		case len(c.Query("from")) == 0:
+			// the range without the first day is open-ended, it includes all the tasks due up to the last one
+			to, err = parseDay(c.Query("to"), location)
+		default:
+			from, to, err = getDateRangeQuery(c, location)
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return entity.TaskFilter{}, false
+		}
+
+		filter.From, filter.To = from, to.AddDate(0, 0, 1)
 	}
 
-	tasks, err := h.services.Agenda.GetByDateAndStatus(c, c.GetInt(userCtx), status, date, input.Limit, (page-1)*input.Offset)
+	return filter, true
+}
+
+func (h *Handler) listTasksPage(c *gin.Context, filter entity.TaskFilter) {
+	page, err := h.services.Agenda.ListTasks(c, c.GetInt(userCtx), filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidStatus) ||
+			errors.Is(err, entity.ErrInvalidPriority) ||
+			errors.Is(err, entity.ErrInvalidFilterQuery) ||
+			errors.Is(err, entity.ErrInvalidDateRange) ||
+			errors.Is(err, entity.ErrInvalidView) ||
+			errors.Is(err, entity.ErrInvalidSort) ||
+			errors.Is(err, entity.ErrInvalidCursor) ||
+			errors.Is(err, entity.ErrInvalidPaginationSizes) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response := listTasksResponse{Tasks: page.Tasks}
+	if page.NextCursor != nil {
+		response.NextCursor = page.NextCursor.Encode()
+	}
+
+	c.Header("X-Total-Count", strconv.Itoa(page.Total))
+	newResponse(c, http.StatusOK, response)
+}
+
+/* --- GET WORKLOAD REPORT --- */
+
+type getWorkloadReportResponse struct {
+	Report entity.WorkloadReport `json:"report"`
+}
+
+// @Summary Get Workload Report
+// @Security Bearer
+// @Description comparing the estimates of the tasks planned per day with the completed ones and the overdue load for the date range (calendar days in the user's timezone)
+// @Tags agenda
+// @Produce json
+// @Param from query string true "First day of the range (2006-01-02)"
+// @Param to query string false "Last day of the range (2006-01-02), default - from"
+// @Success 200 {object} getWorkloadReportResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/workload [get]
+func (h *Handler) getWorkloadReport(c *gin.Context) {
+	location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	newResponse(c, http.StatusOK, getAllUserTasksResponse{Tasks: tasks})
+	from, to, err := getDateRangeQuery(c, location)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.services.Agenda.GetWorkloadReport(c, c.GetInt(userCtx), from, to)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidDateRange) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getWorkloadReportResponse{Report: report})
+}
+
+// parseDate parses RFC 3339 date or date without offset in the user's location.
+func parseDate(value string, location *time.Location) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	for _, layout := range localDateFormats {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, entity.ErrInvalidData
+}
+
+// parseDay returns the start of the calendar day (in the user's location) the date falls on.
+func parseDay(value string, location *time.Location) (time.Time, error) {
+	date, err := parseDate(value, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	date = date.In(location)
+
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location), nil
+}
+
+// getDateRangeQuery returns the starts of the first and the last day of the from-to range,
+// to defaults to from.
+func getDateRangeQuery(c *gin.Context, location *time.Location) (time.Time, time.Time, error) {
+	from, err := parseDay(c.Query("from"), location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to := from
+	if value, ok := c.GetQuery("to"); ok {
+		if to, err = parseDay(value, location); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	return from, to, nil
+}
+
+func getTagsQuery(c *gin.Context) ([]string, bool) {
+	var tags []string
+	for _, value := range c.QueryArray("tag") {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+
+	return tags, c.Query("tag_mode") == "all"
+}
+
+func getProjectQuery(c *gin.Context) (*int, error) {
+	param, ok := c.GetQuery("project_id")
+	if !ok {
+		return nil, nil
+	}
+
+	projectId, err := strconv.Atoi(param)
+	if err != nil || projectId < 0 {
+		return nil, entity.ErrInvalidInput
+	}
+
+	return &projectId, nil
 }
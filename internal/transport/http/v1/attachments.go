@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initAttachmentsRoutes(api *gin.RouterGroup) {
+	attachments := api.Group("/agenda/:task_id/attachments", h.userIdentity)
+	{
+		attachments.POST("", h.uploadAttachment)
+		attachments.GET("", h.getTaskAttachments)
+		attachments.GET("/:attachment_id", h.downloadAttachment)
+		attachments.DELETE("/:attachment_id", h.deleteAttachmentByID)
+	}
+}
+
+/* --- UPLOAD ATTACHMENT --- */
+
+type uploadAttachmentResponse struct {
+	ID int `json:"id"`
+}
+
+// multipartOverhead is the room for the multipart boundaries and headers in the upload request body.
+const multipartOverhead = 64 << 10
+
+// @Summary Upload Attachment
+// @Security Bearer
+// @Description upload a file to the task (the size of a file and the total size of user's files are limited)
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param file formData file true "File"
+// @Success 201 {object} uploadAttachmentResponse
+// @Failure 400 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/attachments [post]
+func (h *Handler) uploadAttachment(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	// the body is limited before parsing, so a too large file is not read (and buffered) entirely
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.services.Attachments.MaxFileSize()+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			newErrorResponse(c, http.StatusRequestEntityTooLarge, entity.ErrAttachmentTooLarge.Error())
+		} else {
+			newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		}
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	id, err := h.services.Attachments.UploadAttachment(c, entity.Attachment{
+		TaskID:   taskId,
+		UserID:   c.GetInt(userCtx),
+		Filename: header.Filename,
+		Size:     header.Size,
+	}, file)
+	if err != nil {
+		if errors.Is(err, entity.ErrAttachmentTooLarge) || errors.Is(err, entity.ErrStorageQuotaExceeded) {
+			newErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+		} else if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, uploadAttachmentResponse{ID: id})
+}
+
+/* --- GET TASK ATTACHMENTS --- */
+
+type getTaskAttachmentsResponse struct {
+	Attachments []entity.Attachment `json:"attachments"`
+}
+
+// @Summary Get Task Attachments
+// @Security Bearer
+// @Description getting the metadata of all files uploaded to the task
+// @Tags attachments
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getTaskAttachmentsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/attachments [get]
+func (h *Handler) getTaskAttachments(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	attachments, err := h.services.Attachments.GetTaskAttachments(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTaskAttachmentsResponse{Attachments: attachments})
+}
+
+/* --- DOWNLOAD ATTACHMENT --- */
+
+// @Summary Download Attachment
+// @Security Bearer
+// @Description downloading the file content
+// @Tags attachments
+// @Produce octet-stream
+// @Param task_id path int true "Task ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/attachments/:attachment_id [get]
+func (h *Handler) downloadAttachment(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "attachment_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	attachment, content, err := h.services.Attachments.DownloadAttachment(c, id, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrAttachmentDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+/* --- DELETE ATTACHMENT BY ID --- */
+
+// @Summary Delete Attachment By ID
+// @Security Bearer
+// @Description deleting the file from the task
+// @Tags attachments
+// @Param task_id path int true "Task ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/attachments/:attachment_id [delete]
+func (h *Handler) deleteAttachmentByID(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "attachment_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Attachments.DeleteAttachmentByID(c, id, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrAttachmentDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
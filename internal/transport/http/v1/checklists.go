@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initChecklistsRoutes(api *gin.RouterGroup) {
+	checklist := api.Group("/agenda/:task_id/checklist", h.userIdentity)
+	{
+		checklist.POST("", h.addChecklistItem)
+		checklist.GET("", h.getChecklist)
+		checklist.PATCH("/:item_id", h.updateChecklistItem)
+		checklist.DELETE("/:item_id", h.deleteChecklistItem)
+	}
+}
+
+/* --- ADD CHECKLIST ITEM --- */
+
+type addChecklistItemInput struct {
+	Text string `json:"text" binding:"required,max=255"`
+}
+
+type addChecklistItemResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Add Checklist Item
+// @Security Bearer
+// @Description add an item to the end of the task checklist
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param input body addChecklistItemInput true "input"
+// @Success 201 {object} addChecklistItemResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/checklist [post]
+func (h *Handler) addChecklistItem(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	var input addChecklistItemInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.Checklists.AddChecklistItem(c, taskId, c.GetInt(userCtx), input.Text)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, addChecklistItemResponse{ID: id})
+}
+
+/* --- GET CHECKLIST --- */
+
+type getChecklistResponse struct {
+	Checklist []entity.ChecklistItem `json:"checklist"`
+	Progress  *int                   `json:"progress,omitempty"`
+}
+
+// @Summary Get Checklist
+// @Security Bearer
+// @Description getting the task checklist in order with the percentage of checked items
+// @Tags checklist
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getChecklistResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/checklist [get]
+func (h *Handler) getChecklist(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	checklist, err := h.services.Checklists.GetChecklist(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getChecklistResponse{
+		Checklist: checklist,
+		Progress:  entity.ChecklistProgress(checklist),
+	})
+}
+
+/* --- UPDATE CHECKLIST ITEM --- */
+
+type updateChecklistItemInput struct {
+	Text     *string `json:"text" binding:"omitempty,max=255"`
+	Checked  *bool   `json:"checked"`
+	Position *int    `json:"position" binding:"omitempty,min=0"`
+}
+
+// @Summary Update Checklist Item
+// @Security Bearer
+// @Description updating the item text, checking it or moving it to another position (starting from 0)
+// @Tags checklist
+// @Accept json
+// @Param task_id path int true "Task ID"
+// @Param item_id path int true "Item ID"
+// @Param input body updateChecklistItemInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/checklist/:item_id [patch]
+func (h *Handler) updateChecklistItem(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "item_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input updateChecklistItemInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Checklists.UpdateChecklistItem(c, id, taskId, c.GetInt(userCtx), entity.ChecklistItemUpdate{
+		Text:     input.Text,
+		Checked:  input.Checked,
+		Position: input.Position,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrChecklistItemDoesNotExist) ||
+			errors.Is(err, entity.ErrEmptyTaskUpdate) || errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE CHECKLIST ITEM --- */
+
+// @Summary Delete Checklist Item
+// @Security Bearer
+// @Description deleting the item from the task checklist
+// @Tags checklist
+// @Param task_id path int true "Task ID"
+// @Param item_id path int true "Item ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/checklist/:item_id [delete]
+func (h *Handler) deleteChecklistItem(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "item_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Checklists.DeleteChecklistItem(c, id, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrChecklistItemDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initCommentsRoutes(api *gin.RouterGroup) {
+	comments := api.Group("/agenda/:task_id/comments", h.userIdentity)
+	{
+		comments.POST("", h.createComment)
+		comments.GET("", h.getTaskComments)
+		comments.PUT("/:comment_id", h.editComment)
+		comments.DELETE("/:comment_id", h.deleteCommentByID)
+	}
+}
+
+/* --- CREATE COMMENT --- */
+
+type commentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type createCommentResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Create Comment
+// @Security Bearer
+// @Description leave a comment on the task
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param input body commentInput true "input"
+// @Success 201 {object} createCommentResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/comments [post]
+func (h *Handler) createComment(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	var input commentInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.Comments.CreateComment(c, entity.Comment{
+		TaskID: taskId,
+		UserID: c.GetInt(userCtx),
+		Body:   input.Body,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, createCommentResponse{ID: id})
+}
+
+/* --- GET TASK COMMENTS --- */
+
+type getTaskCommentsResponse struct {
+	Comments []entity.Comment `json:"comments"`
+}
+
+// @Summary Get Task Comments
+// @Security Bearer
+// @Description getting all comments of the task from the oldest one
+// @Tags comments
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getTaskCommentsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/comments [get]
+func (h *Handler) getTaskComments(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	comments, err := h.services.Comments.GetTaskComments(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTaskCommentsResponse{Comments: comments})
+}
+
+/* --- EDIT COMMENT --- */
+
+// @Summary Edit Comment
+// @Security Bearer
+// @Description editing the comment text (only by its author)
+// @Tags comments
+// @Accept json
+// @Param task_id path int true "Task ID"
+// @Param comment_id path int true "Comment ID"
+// @Param input body commentInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/comments/:comment_id [put]
+func (h *Handler) editComment(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "comment_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input commentInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Comments.EditComment(c, id, taskId, c.GetInt(userCtx), input.Body)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrCommentDoesNotExist) ||
+			errors.Is(err, entity.ErrNotCommentAuthor) || errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE COMMENT BY ID --- */
+
+// @Summary Delete Comment By ID
+// @Security Bearer
+// @Description deleting the comment (only by its author)
+// @Tags comments
+// @Param task_id path int true "Task ID"
+// @Param comment_id path int true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/comments/:comment_id [delete]
+func (h *Handler) deleteCommentByID(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "comment_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Comments.DeleteCommentByID(c, id, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrCommentDoesNotExist) ||
+			errors.Is(err, entity.ErrNotCommentAuthor) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
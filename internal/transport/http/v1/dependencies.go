@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initDependenciesRoutes(api *gin.RouterGroup) {
+	dependencies := api.Group("/agenda/:task_id/dependencies", h.userIdentity)
+	{
+		dependencies.POST("", h.addDependency)
+		dependencies.GET("", h.getBlockers)
+		dependencies.DELETE("/:blocker_id", h.removeDependency)
+	}
+}
+
+/* --- ADD DEPENDENCY --- */
+
+type addDependencyInput struct {
+	BlockerID int `json:"blocker_id" binding:"required"`
+}
+
+// @Summary Add Dependency
+// @Security Bearer
+// @Description make the task blocked by another task (dependencies creating a cycle are refused)
+// @Tags dependencies
+// @Accept json
+// @Param task_id path int true "Task ID"
+// @Param input body addDependencyInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/dependencies [post]
+func (h *Handler) addDependency(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	var input addDependencyInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Dependencies.AddDependency(c, taskId, input.BlockerID, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrDependencyAlreadyExists) || errors.Is(err, entity.ErrDependencyCycle) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrBlockerDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- GET BLOCKERS --- */
+
+type getBlockersResponse struct {
+	BlockedBy []entity.Task `json:"blocked_by"`
+}
+
+// @Summary Get Blockers
+// @Security Bearer
+// @Description getting all tasks the task is blocked by
+// @Tags dependencies
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getBlockersResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/dependencies [get]
+func (h *Handler) getBlockers(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	blockers, err := h.services.Dependencies.GetBlockers(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getBlockersResponse{BlockedBy: blockers})
+}
+
+/* --- REMOVE DEPENDENCY --- */
+
+// @Summary Remove Dependency
+// @Security Bearer
+// @Description removing the task dependency on the blocker task
+// @Tags dependencies
+// @Param task_id path int true "Task ID"
+// @Param blocker_id path int true "Blocker Task ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/dependencies/:blocker_id [delete]
+func (h *Handler) removeDependency(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	blockerId, err := getIdParam(c, "blocker_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (blocker_id)")
+		return
+	}
+
+	err = h.services.Dependencies.RemoveDependency(c, taskId, blockerId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrDependencyDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/zenorachi/todo-service/internal/service"
 	"github.com/zenorachi/todo-service/pkg/auth"
@@ -23,5 +26,20 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 	{
 		h.initUsersRoutes(v1)
 		h.initAgendaRoutes(v1)
+		h.initTagsRoutes(v1)
+		h.initProjectsRoutes(v1)
+		h.initRemindersRoutes(v1)
+		h.initStatusesRoutes(v1)
+		h.initDependenciesRoutes(v1)
+		h.initHistoryRoutes(v1)
+		h.initCommentsRoutes(v1)
+		h.initAttachmentsRoutes(v1)
+		h.initChecklistsRoutes(v1)
+		h.initTimeEntriesRoutes(v1)
+		h.initTemplatesRoutes(v1)
 	}
 }
+
+func getIdParam(c *gin.Context, param string) (int, error) {
+	return strconv.Atoi(strings.Trim(c.Param(param), "/"))
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initHistoryRoutes(api *gin.RouterGroup) {
+	history := api.Group("/agenda/:task_id/history", h.userIdentity)
+	{
+		history.GET("", h.getTaskHistory)
+	}
+}
+
+/* --- GET TASK HISTORY --- */
+
+type getTaskHistoryResponse struct {
+	History []entity.HistoryEntry `json:"history"`
+}
+
+// @Summary Get Task History
+// @Security Bearer
+// @Description getting all changes of the task in chronological order (created, updated, status_changed, moved, reordered, deleted, restored, archived, unarchived), the history is kept after the task is purged from the trash
+// @Tags history
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getTaskHistoryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/history [get]
+func (h *Handler) getTaskHistory(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	history, err := h.services.History.GetTaskHistory(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTaskHistoryResponse{History: history})
+}
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initProjectsRoutes(api *gin.RouterGroup) {
+	projects := api.Group("/projects", h.userIdentity)
+	{
+		projects.POST("", h.createProject)
+		projects.GET("", h.getUserProjects)
+		projects.GET("/:project_id", h.getProjectByID)
+		projects.PUT("/:project_id", h.updateProject)
+		projects.DELETE("/:project_id", h.deleteProjectByID)
+	}
+}
+
+/* --- CREATE PROJECT --- */
+
+type projectInput struct {
+	Name        string `json:"name" binding:"required,min=1,max=255"`
+	Description string `json:"description"`
+}
+
+type createProjectResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Create project
+// @Security Bearer
+// @Description create project
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param input body projectInput true "input"
+// @Success 201 {object} createProjectResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects [post]
+func (h *Handler) createProject(c *gin.Context) {
+	var input projectInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.Projects.CreateProject(c, entity.Project{
+		UserID:      c.GetInt(userCtx),
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrProjectAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, createProjectResponse{ID: id})
+}
+
+/* --- GET ALL USER PROJECTS --- */
+
+type getUserProjectsResponse struct {
+	Projects []entity.Project `json:"projects"`
+}
+
+// @Summary Get All User Projects
+// @Security Bearer
+// @Description getting all user projects
+// @Tags projects
+// @Produce json
+// @Success 200 {object} getUserProjectsResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects [get]
+func (h *Handler) getUserProjects(c *gin.Context) {
+	projects, err := h.services.Projects.GetUserProjects(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	newResponse(c, http.StatusOK, getUserProjectsResponse{Projects: projects})
+}
+
+/* --- GET PROJECT BY ID --- */
+
+type getProjectByIDResponse struct {
+	Project entity.Project `json:"project"`
+}
+
+// @Summary Get Project By ID
+// @Security Bearer
+// @Description getting project by id
+// @Tags projects
+// @Produce json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} getProjectByIDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/:project_id [get]
+func (h *Handler) getProjectByID(c *gin.Context) {
+	id, err := getIdParam(c, "project_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	project, err := h.services.Projects.GetProjectByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrProjectDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getProjectByIDResponse{Project: project})
+}
+
+/* --- UPDATE PROJECT --- */
+
+// @Summary Update Project
+// @Security Bearer
+// @Description updating project name and description by id
+// @Tags projects
+// @Accept json
+// @Param project_id path int true "Project ID"
+// @Param input body projectInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/:project_id [put]
+func (h *Handler) updateProject(c *gin.Context) {
+	id, err := getIdParam(c, "project_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input projectInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Projects.UpdateProject(c, entity.Project{
+		ID:          id,
+		UserID:      c.GetInt(userCtx),
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrProjectAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrProjectDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE PROJECT BY ID --- */
+
+// @Summary Delete Project By ID
+// @Security Bearer
+// @Description deleting project by id (tasks of the project are kept without project)
+// @Tags projects
+// @Param project_id path int true "Project ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/projects/:project_id [delete]
+func (h *Handler) deleteProjectByID(c *gin.Context) {
+	id, err := getIdParam(c, "project_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Projects.DeleteProjectByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrProjectDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
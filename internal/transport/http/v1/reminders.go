@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initRemindersRoutes(api *gin.RouterGroup) {
+	reminders := api.Group("/agenda/:task_id/reminders", h.userIdentity)
+	{
+		reminders.POST("", h.createReminder)
+		reminders.GET("", h.getTaskReminders)
+		reminders.DELETE("/:reminder_id", h.deleteReminderByID)
+	}
+}
+
+/* --- CREATE REMINDER --- */
+
+type createReminderInput struct {
+	RemindAt      *string `json:"remind_at"`
+	OffsetMinutes *int    `json:"offset_minutes"`
+}
+
+type createReminderResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Create Reminder
+// @Security Bearer
+// @Description create task reminder at the absolute time (remind_at) or offset_minutes before the task date
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param input body createReminderInput true "input"
+// @Success 201 {object} createReminderResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/reminders [post]
+func (h *Handler) createReminder(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	var input createReminderInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	reminder := entity.Reminder{
+		UserID:        c.GetInt(userCtx),
+		TaskID:        taskId,
+		OffsetMinutes: input.OffsetMinutes,
+	}
+
+	if input.RemindAt != nil {
+		location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		remindAt, err := parseDate(*input.RemindAt, location)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		reminder.RemindAt = &remindAt
+	}
+
+	id, err := h.services.Reminders.CreateReminder(c, reminder)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrInvalidReminder) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, createReminderResponse{ID: id})
+}
+
+/* --- GET TASK REMINDERS --- */
+
+type getTaskRemindersResponse struct {
+	Reminders []entity.Reminder `json:"reminders"`
+}
+
+// @Summary Get Task Reminders
+// @Security Bearer
+// @Description getting all reminders of the task (sent_at is set for already fired ones)
+// @Tags reminders
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getTaskRemindersResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/reminders [get]
+func (h *Handler) getTaskReminders(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	reminders, err := h.services.Reminders.GetTaskReminders(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTaskRemindersResponse{Reminders: reminders})
+}
+
+/* --- DELETE REMINDER BY ID --- */
+
+// @Summary Delete Reminder By ID
+// @Security Bearer
+// @Description deleting task reminder by id
+// @Tags reminders
+// @Param task_id path int true "Task ID"
+// @Param reminder_id path int true "Reminder ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/reminders/:reminder_id [delete]
+func (h *Handler) deleteReminderByID(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "reminder_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Reminders.DeleteReminderByID(c, id, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrReminderDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
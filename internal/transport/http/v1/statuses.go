@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initStatusesRoutes(api *gin.RouterGroup) {
+	statuses := api.Group("/statuses", h.userIdentity)
+	{
+		statuses.POST("", h.createStatus)
+		statuses.GET("", h.getStatuses)
+		statuses.PUT("/:status_id", h.updateStatus)
+		statuses.DELETE("/:status_id", h.deleteStatusByID)
+	}
+}
+
+/* --- CREATE STATUS --- */
+
+type statusInput struct {
+	Name        string   `json:"name" binding:"required,min=1,max=64"`
+	Completed   bool     `json:"completed"`
+	Position    int      `json:"position"`
+	Transitions []string `json:"transitions" binding:"omitempty,dive,min=1,max=64"`
+}
+
+type createStatusInput struct {
+	statusInput
+	ProjectID *int `json:"project_id"`
+}
+
+type createStatusResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Create Status
+// @Security Bearer
+// @Description create workflow status of the user (or of the project, if project_id is set), the default ones (not done, done) stay in the workflow unless a status of the same name is configured
+// @Tags statuses
+// @Accept json
+// @Produce json
+// @Param input body createStatusInput true "input"
+// @Success 201 {object} createStatusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/statuses [post]
+func (h *Handler) createStatus(c *gin.Context) {
+	var input createStatusInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.Statuses.CreateStatus(c, entity.Status{
+		UserID:      c.GetInt(userCtx),
+		ProjectID:   input.ProjectID,
+		Name:        input.Name,
+		Completed:   input.Completed,
+		Position:    input.Position,
+		Transitions: input.Transitions,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrStatusAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrProjectDoesNotExist) || errors.Is(err, entity.ErrInvalidStatus) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, createStatusResponse{ID: id})
+}
+
+/* --- GET STATUSES --- */
+
+type getStatusesResponse struct {
+	Statuses []entity.Status `json:"statuses"`
+}
+
+// @Summary Get Statuses
+// @Security Bearer
+// @Description getting the workflow statuses applied to the tasks of the project (or to the tasks without project)
+// @Tags statuses
+// @Produce json
+// @Param project_id query int false "Project ID (0 - tasks without project)"
+// @Success 200 {object} getStatusesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/statuses [get]
+func (h *Handler) getStatuses(c *gin.Context) {
+	projectId, err := getProjectQuery(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (project_id)")
+		return
+	}
+
+	if projectId != nil && *projectId == 0 {
+		projectId = nil
+	}
+
+	statuses, err := h.services.Statuses.GetStatuses(c, c.GetInt(userCtx), projectId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	newResponse(c, http.StatusOK, getStatusesResponse{Statuses: statuses})
+}
+
+/* --- UPDATE STATUS --- */
+
+// @Summary Update Status
+// @Security Bearer
+// @Description updating workflow status by id (tasks having the status are moved to the new name)
+// @Tags statuses
+// @Accept json
+// @Param status_id path int true "Status ID"
+// @Param input body statusInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/statuses/:status_id [put]
+func (h *Handler) updateStatus(c *gin.Context) {
+	id, err := getIdParam(c, "status_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input statusInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Statuses.UpdateStatus(c, entity.Status{
+		ID:          id,
+		UserID:      c.GetInt(userCtx),
+		Name:        input.Name,
+		Completed:   input.Completed,
+		Position:    input.Position,
+		Transitions: input.Transitions,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrStatusAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrStatusDoesNotExist) || errors.Is(err, entity.ErrInvalidStatus) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE STATUS BY ID --- */
+
+// @Summary Delete Status By ID
+// @Security Bearer
+// @Description deleting workflow status by id (the status should not be used by any task)
+// @Tags statuses
+// @Param status_id path int true "Status ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/statuses/:status_id [delete]
+func (h *Handler) deleteStatusByID(c *gin.Context) {
+	id, err := getIdParam(c, "status_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Statuses.DeleteStatusByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrStatusInUse) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrStatusDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
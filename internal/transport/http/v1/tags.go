@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initTagsRoutes(api *gin.RouterGroup) {
+	tags := api.Group("/tags", h.userIdentity)
+	{
+		tags.POST("", h.createTag)
+		tags.GET("", h.getUserTags)
+		tags.GET("/:tag_id", h.getTagByID)
+		tags.PUT("/:tag_id", h.renameTag)
+		tags.DELETE("/:tag_id", h.deleteTagByID)
+	}
+}
+
+/* --- CREATE TAG --- */
+
+type tagInput struct {
+	Name string `json:"name" binding:"required,min=1,max=64"`
+}
+
+type createTagResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Create tag
+// @Security Bearer
+// @Description create tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param input body tagInput true "input"
+// @Success 201 {object} createTagResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tags [post]
+func (h *Handler) createTag(c *gin.Context) {
+	var input tagInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.Tags.CreateTag(c, entity.Tag{
+		UserID: c.GetInt(userCtx),
+		Name:   input.Name,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrTagAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, createTagResponse{ID: id})
+}
+
+/* --- GET ALL USER TAGS --- */
+
+type getUserTagsResponse struct {
+	Tags []entity.Tag `json:"tags"`
+}
+
+// @Summary Get All User Tags
+// @Security Bearer
+// @Description getting all user tags
+// @Tags tags
+// @Produce json
+// @Success 200 {object} getUserTagsResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tags [get]
+func (h *Handler) getUserTags(c *gin.Context) {
+	tags, err := h.services.Tags.GetUserTags(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	newResponse(c, http.StatusOK, getUserTagsResponse{Tags: tags})
+}
+
+/* --- GET TAG BY ID --- */
+
+type getTagByIDResponse struct {
+	Tag entity.Tag `json:"tag"`
+}
+
+// @Summary Get Tag By ID
+// @Security Bearer
+// @Description getting tag by id
+// @Tags tags
+// @Produce json
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {object} getTagByIDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tags/:tag_id [get]
+func (h *Handler) getTagByID(c *gin.Context) {
+	id, err := getIdParam(c, "tag_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	tag, err := h.services.Tags.GetTagByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTagDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTagByIDResponse{Tag: tag})
+}
+
+/* --- RENAME TAG --- */
+
+// @Summary Rename Tag
+// @Security Bearer
+// @Description renaming tag by id
+// @Tags tags
+// @Accept json
+// @Param tag_id path int true "Tag ID"
+// @Param input body tagInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tags/:tag_id [put]
+func (h *Handler) renameTag(c *gin.Context) {
+	id, err := getIdParam(c, "tag_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input tagInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Tags.RenameTag(c, id, c.GetInt(userCtx), input.Name)
+	if err != nil {
+		if errors.Is(err, entity.ErrTagAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTagDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE TAG BY ID --- */
+
+// @Summary Delete Tag By ID
+// @Security Bearer
+// @Description deleting tag by id (the tag is also detached from all tasks)
+// @Tags tags
+// @Param tag_id path int true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tags/:tag_id [delete]
+func (h *Handler) deleteTagByID(c *gin.Context) {
+	id, err := getIdParam(c, "tag_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Tags.DeleteTagByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTagDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
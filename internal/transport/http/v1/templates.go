@@ -0,0 +1,290 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initTemplatesRoutes(api *gin.RouterGroup) {
+	templates := api.Group("/templates", h.userIdentity)
+	{
+		templates.POST("", h.createTemplate)
+		templates.GET("", h.getUserTemplates)
+		templates.GET("/:template_id", h.getTemplateByID)
+		templates.PUT("/:template_id", h.updateTemplate)
+		templates.DELETE("/:template_id", h.deleteTemplateByID)
+		templates.POST("/:template_id/instantiate", h.instantiateTemplate)
+	}
+}
+
+/* --- CREATE TEMPLATE --- */
+
+type templateInput struct {
+	Name             string   `json:"name" binding:"required,min=1,max=64"`
+	Title            string   `json:"title" binding:"required,min=2,max=255"`
+	Description      string   `json:"description"`
+	DueOffsetMinutes int      `json:"due_offset_minutes" binding:"min=0"`
+	Tags             []string `json:"tags" binding:"omitempty,max=16,dive,min=1,max=64"`
+	Checklist        []string `json:"checklist" binding:"omitempty,max=64,dive,min=1,max=255"`
+}
+
+type createTemplateResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Create Template
+// @Security Bearer
+// @Description create task template, `{{name}}` variables in the title and description are substituted on instantiation
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param input body templateInput true "input"
+// @Success 201 {object} createTemplateResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/templates [post]
+func (h *Handler) createTemplate(c *gin.Context) {
+	var input templateInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.Templates.CreateTemplate(c, input.toTemplate(0, c.GetInt(userCtx)))
+	if err != nil {
+		if errors.Is(err, entity.ErrTemplateAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, createTemplateResponse{ID: id})
+}
+
+func (i templateInput) toTemplate(id, userId int) entity.Template {
+	return entity.Template{
+		ID:               id,
+		UserID:           userId,
+		Name:             i.Name,
+		Title:            i.Title,
+		Description:      i.Description,
+		DueOffsetMinutes: i.DueOffsetMinutes,
+		Tags:             i.Tags,
+		Checklist:        i.Checklist,
+	}
+}
+
+/* --- GET ALL USER TEMPLATES --- */
+
+type getUserTemplatesResponse struct {
+	Templates []entity.Template `json:"templates"`
+}
+
+// @Summary Get All User Templates
+// @Security Bearer
+// @Description getting all user task templates
+// @Tags templates
+// @Produce json
+// @Success 200 {object} getUserTemplatesResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/templates [get]
+func (h *Handler) getUserTemplates(c *gin.Context) {
+	templates, err := h.services.Templates.GetUserTemplates(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	newResponse(c, http.StatusOK, getUserTemplatesResponse{Templates: templates})
+}
+
+/* --- GET TEMPLATE BY ID --- */
+
+type getTemplateByIDResponse struct {
+	Template  entity.Template `json:"template"`
+	Variables []string        `json:"variables"`
+}
+
+// @Summary Get Template By ID
+// @Security Bearer
+// @Description getting task template by id together with the names of its variables
+// @Tags templates
+// @Produce json
+// @Param template_id path int true "Template ID"
+// @Success 200 {object} getTemplateByIDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/templates/:template_id [get]
+func (h *Handler) getTemplateByID(c *gin.Context) {
+	id, err := getIdParam(c, "template_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	template, err := h.services.Templates.GetTemplateByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTemplateDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTemplateByIDResponse{Template: template, Variables: template.Variables()})
+}
+
+/* --- UPDATE TEMPLATE --- */
+
+// @Summary Update Template
+// @Security Bearer
+// @Description replacing task template by id (the tasks created from it are not changed)
+// @Tags templates
+// @Accept json
+// @Param template_id path int true "Template ID"
+// @Param input body templateInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/templates/:template_id [put]
+func (h *Handler) updateTemplate(c *gin.Context) {
+	id, err := getIdParam(c, "template_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input templateInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err = h.services.Templates.UpdateTemplate(c, input.toTemplate(id, c.GetInt(userCtx)))
+	if err != nil {
+		if errors.Is(err, entity.ErrTemplateAlreadyExists) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTemplateDoesNotExist) || errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE TEMPLATE BY ID --- */
+
+// @Summary Delete Template By ID
+// @Security Bearer
+// @Description deleting task template by id (the tasks created from it are kept)
+// @Tags templates
+// @Param template_id path int true "Template ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/templates/:template_id [delete]
+func (h *Handler) deleteTemplateByID(c *gin.Context) {
+	id, err := getIdParam(c, "template_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.Templates.DeleteTemplateByID(c, id, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTemplateDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- INSTANTIATE TEMPLATE --- */
+
+type instantiateTemplateInput struct {
+	Variables map[string]string `json:"variables"`
+	Date      string            `json:"date" binding:"omitempty,min=6,max=64"`
+	ProjectID *int              `json:"project_id"`
+}
+
+type instantiateTemplateResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Instantiate Template
+// @Security Bearer
+// @Description creating task from the template, every variable used in the template should have a value (the task is due after the offset of the template, unless the date is set)
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template_id path int true "Template ID"
+// @Param input body instantiateTemplateInput true "input"
+// @Success 201 {object} instantiateTemplateResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/templates/:template_id/instantiate [post]
+func (h *Handler) instantiateTemplate(c *gin.Context) {
+	id, err := getIdParam(c, "template_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input instantiateTemplateInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	var date *time.Time
+	if len(input.Date) != 0 {
+		location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		parsed, err := parseDate(input.Date, location)
+		if err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		date = &parsed
+	}
+
+	taskId, err := h.services.Templates.InstantiateTemplate(c, id, c.GetInt(userCtx), input.Variables, date, input.ProjectID)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskAlreadyExist) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTemplateDoesNotExist) ||
+			errors.Is(err, entity.ErrMissingTemplateVariables) ||
+			errors.Is(err, entity.ErrProjectDoesNotExist) ||
+			errors.Is(err, entity.ErrInvalidInput) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, instantiateTemplateResponse{ID: taskId})
+}
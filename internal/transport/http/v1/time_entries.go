@@ -0,0 +1,378 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zenorachi/todo-service/internal/entity"
+)
+
+func (h *Handler) initTimeEntriesRoutes(api *gin.RouterGroup) {
+	entries := api.Group("/agenda/:task_id/time", h.userIdentity)
+	{
+		entries.POST("/start", h.startTimer)
+		entries.POST("", h.addTimeEntry)
+		entries.GET("", h.getTimeEntries)
+		entries.PATCH("/:entry_id", h.updateTimeEntry)
+		entries.DELETE("/:entry_id", h.deleteTimeEntry)
+	}
+
+	timer := api.Group("/time", h.userIdentity)
+	{
+		timer.POST("/stop", h.stopTimer)
+		timer.GET("/report", h.getTimeReport)
+	}
+}
+
+/* --- START TIMER --- */
+
+type startTimerInput struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+type startTimerResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Start Timer
+// @Security Bearer
+// @Description start tracking time on the task (only one timer of the user can run at a time)
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param input body startTimerInput true "input"
+// @Success 201 {object} startTimerResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/time/start [post]
+func (h *Handler) startTimer(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	var input startTimerInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	id, err := h.services.TimeEntries.StartTimer(c, taskId, c.GetInt(userCtx), input.Note)
+	if err != nil {
+		if errors.Is(err, entity.ErrTimerAlreadyRunning) {
+			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, startTimerResponse{ID: id})
+}
+
+/* --- STOP TIMER --- */
+
+type stopTimerResponse struct {
+	Entry entity.TimeEntry `json:"entry"`
+}
+
+// @Summary Stop Timer
+// @Security Bearer
+// @Description stop the running timer of the user
+// @Tags time
+// @Produce json
+// @Success 200 {object} stopTimerResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/time/stop [post]
+func (h *Handler) stopTimer(c *gin.Context) {
+	entry, err := h.services.TimeEntries.StopTimer(c, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTimerIsNotRunning) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, stopTimerResponse{Entry: entry})
+}
+
+/* --- ADD TIME ENTRY --- */
+
+type addTimeEntryInput struct {
+	StartedAt string `json:"started_at" binding:"required,min=6,max=64"`
+	StoppedAt string `json:"stopped_at" binding:"required,min=6,max=64"`
+	Note      string `json:"note" binding:"max=255"`
+}
+
+type addTimeEntryResponse struct {
+	ID int `json:"id"`
+}
+
+// @Summary Add Time Entry
+// @Security Bearer
+// @Description add the time spent on the task manually (dates without offset are in the user's timezone)
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Param input body addTimeEntryInput true "input"
+// @Success 201 {object} addTimeEntryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/time [post]
+func (h *Handler) addTimeEntry(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	var input addTimeEntryInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	startedAt, err := parseDate(input.StartedAt, location)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stoppedAt, err := parseDate(input.StoppedAt, location)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.TimeEntries.AddTimeEntry(c, entity.TimeEntry{
+		TaskID:    taskId,
+		UserID:    c.GetInt(userCtx),
+		StartedAt: startedAt,
+		StoppedAt: &stoppedAt,
+		Note:      input.Note,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrInvalidTimeEntry) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusCreated, addTimeEntryResponse{ID: id})
+}
+
+/* --- GET TIME ENTRIES --- */
+
+type getTimeEntriesResponse struct {
+	Entries []entity.TimeEntry `json:"entries"`
+}
+
+// @Summary Get Time Entries
+// @Security Bearer
+// @Description getting the time entries of the task in chronological order (duration is in seconds)
+// @Tags time
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} getTimeEntriesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/time [get]
+func (h *Handler) getTimeEntries(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	entries, err := h.services.TimeEntries.GetTimeEntries(c, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTimeEntriesResponse{Entries: entries})
+}
+
+/* --- UPDATE TIME ENTRY --- */
+
+type updateTimeEntryInput struct {
+	StartedAt *string `json:"started_at" binding:"omitempty,min=6,max=64"`
+	StoppedAt *string `json:"stopped_at" binding:"omitempty,min=6,max=64"`
+	Note      *string `json:"note" binding:"omitempty,max=255"`
+}
+
+// @Summary Update Time Entry
+// @Security Bearer
+// @Description updating the time entry, setting stopped_at of a running timer stops it
+// @Tags time
+// @Accept json
+// @Param task_id path int true "Task ID"
+// @Param entry_id path int true "Entry ID"
+// @Param input body updateTimeEntryInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/time/:entry_id [patch]
+func (h *Handler) updateTimeEntry(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "entry_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	var input updateTimeEntryInput
+	if err = c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	update := entity.TimeEntryUpdate{Note: input.Note}
+
+	if input.StartedAt != nil || input.StoppedAt != nil {
+		location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if input.StartedAt != nil {
+			startedAt, err := parseDate(*input.StartedAt, location)
+			if err != nil {
+				newErrorResponse(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			update.StartedAt = &startedAt
+		}
+
+		if input.StoppedAt != nil {
+			stoppedAt, err := parseDate(*input.StoppedAt, location)
+			if err != nil {
+				newErrorResponse(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			update.StoppedAt = &stoppedAt
+		}
+	}
+
+	err = h.services.TimeEntries.UpdateTimeEntry(c, id, taskId, c.GetInt(userCtx), update)
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrTimeEntryDoesNotExist) ||
+			errors.Is(err, entity.ErrEmptyTaskUpdate) || errors.Is(err, entity.ErrInvalidTimeEntry) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- DELETE TIME ENTRY --- */
+
+// @Summary Delete Time Entry
+// @Security Bearer
+// @Description deleting the time entry of the task
+// @Tags time
+// @Param task_id path int true "Task ID"
+// @Param entry_id path int true "Entry ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/agenda/:task_id/time/:entry_id [delete]
+func (h *Handler) deleteTimeEntry(c *gin.Context) {
+	taskId, err := getIdParam(c, "task_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (task_id)")
+		return
+	}
+
+	id, err := getIdParam(c, "entry_id")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid parameter (id)")
+		return
+	}
+
+	err = h.services.TimeEntries.DeleteTimeEntry(c, id, taskId, c.GetInt(userCtx))
+	if err != nil {
+		if errors.Is(err, entity.ErrTaskDoesNotExist) || errors.Is(err, entity.ErrTimeEntryDoesNotExist) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
+
+/* --- GET TIME REPORT --- */
+
+type getTimeReportResponse struct {
+	Report entity.TimeReport `json:"report"`
+}
+
+// @Summary Get Time Report
+// @Security Bearer
+// @Description getting the time tracked per day and per task for the date range (calendar days in the user's timezone, durations are in seconds)
+// @Tags time
+// @Produce json
+// @Param from query string true "First day of the range (2006-01-02)"
+// @Param to query string false "Last day of the range (2006-01-02), default - from"
+// @Success 200 {object} getTimeReportResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/time/report [get]
+func (h *Handler) getTimeReport(c *gin.Context) {
+	location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	from, to, err := getDateRangeQuery(c, location)
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.services.TimeEntries.GetTimeReport(c, c.GetInt(userCtx), from, to)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidDateRange) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusOK, getTimeReportResponse{Report: report})
+}
@@ -16,6 +16,12 @@ func (h *Handler) initUsersRoutes(api *gin.RouterGroup) {
 		users.POST("/sign-in", h.signIn)
 		users.GET("/refresh", h.refresh)
 	}
+
+	settings := api.Group("/users", h.userIdentity)
+	{
+		settings.GET("/timezone", h.getTimezone)
+		settings.PUT("/timezone", h.setTimezone)
+	}
 }
 
 /* --- REGISTRATION --- */
@@ -24,6 +30,7 @@ type signUpInput struct {
 	Login    string `json:"login"    binding:"required,min=2,max=64"`
 	Email    string `json:"email"    binding:"required,email,max=64"`
 	Password string `json:"password" binding:"required,min=6,max=64"`
+	Timezone string `json:"timezone" binding:"max=64"`
 }
 
 type signUpResponse struct {
@@ -31,7 +38,7 @@ type signUpResponse struct {
 }
 
 // @Summary User SignUp
-// @Description create user account
+// @Description create user account (timezone is an IANA time zone, default - UTC)
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -48,10 +55,12 @@ func (h *Handler) signUp(c *gin.Context) {
 		return
 	}
 
-	id, err := h.services.Users.SignUp(c, input.Login, input.Email, input.Password)
+	id, err := h.services.Users.SignUp(c, input.Login, input.Email, input.Password, input.Timezone)
 	if err != nil {
 		if errors.Is(err, entity.ErrUserAlreadyExists) {
 			newErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.Is(err, entity.ErrInvalidTimezone) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
 		} else {
 			newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		}
@@ -134,3 +143,63 @@ func (h *Handler) refresh(c *gin.Context) {
 	c.Header("Set-Cookie", fmt.Sprintf("refresh-token=%s; HttpOnly", tokens.RefreshToken))
 	newResponse(c, http.StatusOK, tokenResponse{Token: tokens.AccessToken})
 }
+
+/* --- GET TIMEZONE --- */
+
+type timezoneResponse struct {
+	Timezone string `json:"timezone"`
+}
+
+// @Summary Get Timezone
+// @Security Bearer
+// @Description getting user's timezone (dates without offset and calendar days are evaluated in it)
+// @Tags users
+// @Produce json
+// @Success 200 {object} timezoneResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/timezone [get]
+func (h *Handler) getTimezone(c *gin.Context) {
+	location, err := h.services.Users.GetLocation(c, c.GetInt(userCtx))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	newResponse(c, http.StatusOK, timezoneResponse{Timezone: location.String()})
+}
+
+/* --- SET TIMEZONE --- */
+
+type timezoneInput struct {
+	Timezone string `json:"timezone" binding:"required,max=64"`
+}
+
+// @Summary Set Timezone
+// @Security Bearer
+// @Description setting user's timezone
+// @Tags users
+// @Accept json
+// @Param input body timezoneInput true "input"
+// @Success 204 "No Content"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/users/timezone [put]
+func (h *Handler) setTimezone(c *gin.Context) {
+	var input timezoneInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, entity.ErrInvalidInput.Error())
+		return
+	}
+
+	err := h.services.Users.SetTimezone(c, c.GetInt(userCtx), input.Timezone)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTimezone) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+		} else {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	newResponse(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/config"
+	"github.com/zenorachi/todo-service/internal/service"
+	"github.com/zenorachi/todo-service/pkg/logger"
+)
+
+// TaskArchiver periodically archives the tasks completed longer than the configured period ago.
+type TaskArchiver struct {
+	*periodic
+	agenda    service.Agenda
+	after     time.Duration
+	batchSize int
+}
+
+func NewTaskArchiver(cfg *config.Config, agenda service.Agenda) *TaskArchiver {
+	a := &TaskArchiver{
+		agenda:    agenda,
+		after:     cfg.Archive.After,
+		batchSize: cfg.Archive.BatchSize,
+	}
+	a.periodic = newPeriodic("archive", cfg.Archive.Interval, cfg.Archive.ShutdownTimeout, a.archive)
+
+	return a
+}
+
+// archive archives the tasks batch by batch until a batch is not full.
+func (a *TaskArchiver) archive(ctx context.Context) error {
+	var total int
+	defer func() {
+		if total != 0 {
+			logger.Info("archive", fmt.Sprintf("%d tasks archived", total))
+		}
+	}()
+
+	for ctx.Err() == nil {
+		archived, err := a.agenda.ArchiveCompletedTasks(ctx, a.after, a.batchSize)
+		if err != nil {
+			return err
+		}
+
+		total += archived
+		if archived < a.batchSize {
+			break
+		}
+	}
+
+	return nil
+}
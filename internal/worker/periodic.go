@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/zenorachi/todo-service/pkg/logger"
+)
+
+// periodic runs the job right away and then every interval until it is shut down,
+// the job errors are logged unless they are caused by the shutdown.
+type periodic struct {
+	name            string
+	job             func(ctx context.Context) error
+	interval        time.Duration
+	shutdownTimeout time.Duration
+	cancel          context.CancelFunc
+	done            chan struct{}
+}
+
+func newPeriodic(name string, interval, shutdownTimeout time.Duration, job func(ctx context.Context) error) *periodic {
+	return &periodic{
+		name:            name,
+		job:             job,
+		interval:        interval,
+		shutdownTimeout: shutdownTimeout,
+		done:            make(chan struct{}),
+	}
+}
+
+func (p *periodic) Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := p.job(ctx); err != nil && ctx.Err() == nil {
+				logger.Error(p.name, err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Shutdown stops the runs and waits for the current one to finish.
+func (p *periodic) Shutdown() error {
+	p.cancel()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(p.shutdownTimeout):
+		return context.DeadlineExceeded
+	}
+}
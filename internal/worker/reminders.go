@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/config"
+	"github.com/zenorachi/todo-service/internal/entity"
+	"github.com/zenorachi/todo-service/internal/service"
+	"github.com/zenorachi/todo-service/pkg/logger"
+	"github.com/zenorachi/todo-service/pkg/notify"
+)
+
+// RemindersDispatcher polls for due reminders and hands them to the notifier.
+type RemindersDispatcher struct {
+	*periodic
+	reminders       service.Reminders
+	notifier        notify.Notifier
+	batchSize       int
+	maxAttempts     int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+	notifyTimeout   time.Duration
+}
+
+func NewRemindersDispatcher(cfg *config.Config, reminders service.Reminders, notifier notify.Notifier) *RemindersDispatcher {
+	d := &RemindersDispatcher{
+		reminders:       reminders,
+		notifier:        notifier,
+		batchSize:       cfg.Reminders.BatchSize,
+		maxAttempts:     cfg.Reminders.MaxAttempts,
+		retryBackoff:    cfg.Reminders.RetryBackoff,
+		maxRetryBackoff: cfg.Reminders.MaxRetryBackoff,
+		notifyTimeout:   cfg.Reminders.NotifyTimeout,
+	}
+	d.periodic = newPeriodic("reminders", cfg.Reminders.PollInterval, cfg.Reminders.ShutdownTimeout, d.dispatch)
+
+	return d
+}
+
+// dispatch delivers due reminders batch by batch until there are no more of them,
+// reminders failed to be delivered are released and retried with backoff until they run out of attempts.
+func (d *RemindersDispatcher) dispatch(ctx context.Context) error {
+	for ctx.Err() == nil {
+		reminders, err := d.reminders.ClaimDueReminders(ctx, d.batchSize, d.maxAttempts)
+		if err != nil {
+			return err
+		}
+
+		var failed bool
+		for _, reminder := range reminders {
+			err = d.notify(reminder)
+			if err != nil {
+				failed = true
+				logger.Error("reminders", err.Error())
+				if err = d.reminders.ReleaseReminder(context.Background(), reminder.ReminderID, d.retryBackoff, d.maxRetryBackoff); err != nil {
+					logger.Error("reminders", err.Error())
+				}
+			}
+		}
+
+		if failed || len(reminders) < d.batchSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// notify delivers the reminder within the notify timeout, the claimed batch is delivered even if shutdown has started,
+// so it is not lost.
+func (d *RemindersDispatcher) notify(reminder entity.DueReminder) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.notifyTimeout)
+	defer cancel()
+
+	return d.notifier.Notify(ctx, notify.Notification{
+		UserID:  reminder.UserID,
+		Email:   reminder.Email,
+		TaskID:  reminder.TaskID,
+		Title:   reminder.Title,
+		DueDate: reminder.Date,
+		FireAt:  reminder.FireAt,
+	})
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zenorachi/todo-service/internal/config"
+	"github.com/zenorachi/todo-service/internal/service"
+	"github.com/zenorachi/todo-service/pkg/logger"
+)
+
+// TrashPurger periodically deletes the tasks kept in the trash longer than the retention
+// together with their attachments.
+type TrashPurger struct {
+	*periodic
+	agenda      service.Agenda
+	attachments service.Attachments
+	retention   time.Duration
+}
+
+func NewTrashPurger(cfg *config.Config, agenda service.Agenda, attachments service.Attachments) *TrashPurger {
+	p := &TrashPurger{
+		agenda:      agenda,
+		attachments: attachments,
+		retention:   cfg.Trash.Retention,
+	}
+	p.periodic = newPeriodic("trash", cfg.Trash.PurgeInterval, cfg.Trash.ShutdownTimeout, p.purge)
+
+	return p
+}
+
+func (p *TrashPurger) purge(ctx context.Context) error {
+	purged, err := p.agenda.PurgeTrash(ctx, p.retention)
+	if err != nil {
+		return err
+	}
+
+	if purged != 0 {
+		logger.Info("trash", fmt.Sprintf("%d tasks purged", purged))
+	}
+
+	// attachments of the purged tasks (including the ones left by previous failed runs) are collected separately
+	collected, err := p.attachments.PurgeOrphanedAttachments(ctx)
+	if err != nil {
+		return err
+	}
+
+	if collected != 0 {
+		logger.Info("trash", fmt.Sprintf("%d attachments purged", collected))
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The query is a sequence of terms matched together, OR between terms matches any of them
+// (AND binds tighter), a term prefixed with "-" is negated and parentheses group terms:
+//
+//	status:done priority>=high due<2024-06-01 -tag:home "quarterly report"
+//	(tag:work OR tag:home) -status:done
+//
+// A term is either a condition `field op value` with one of the operators : = != < <= > >=
+// (":" is the same as "="), or a free text (a word or a quoted phrase). Which fields and operators
+// are supported is up to the code compiling the expression.
+
+const (
+	maxLength = 1024
+	maxDepth  = 32
+)
+
+type Op string
+
+const (
+	Equal          Op = "="
+	NotEqual       Op = "!="
+	Less           Op = "<"
+	LessOrEqual    Op = "<="
+	Greater        Op = ">"
+	GreaterOrEqual Op = ">="
+)
+
+// Expr is a node of the query syntax tree.
+type Expr interface {
+	Pos() int
+}
+
+type (
+	And struct {
+		Left, Right Expr
+	}
+
+	Or struct {
+		Left, Right Expr
+	}
+
+	Not struct {
+		Expr     Expr
+		Position int
+	}
+
+	// Condition is a `field op value` term, the positions point at the field, the operator and the value.
+	Condition struct {
+		Field    string
+		Op       Op
+		Value    string
+		Position int
+		OpPos    int
+		ValuePos int
+	}
+
+	// Text is a free text term.
+	Text struct {
+		Value    string
+		Position int
+	}
+)
+
+func (e *And) Pos() int       { return e.Left.Pos() }
+func (e *Or) Pos() int        { return e.Left.Pos() }
+func (e *Not) Pos() int       { return e.Position }
+func (e *Condition) Pos() int { return e.Position }
+func (e *Text) Pos() int      { return e.Position }
+
+// Error points at the offending token of the query, Pos is the 1-based position of its first character.
+type Error struct {
+	Pos   int
+	Token string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// Errorf returns the error pointing at the token at the position.
+func Errorf(pos int, token, format string, args ...any) *Error {
+	return &Error{Pos: pos, Token: token, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Parse builds the syntax tree of the query, nil is returned for an empty query.
+func Parse(query string) (Expr, error) {
+	if len(query) > maxLength {
+		return nil, Errorf(maxLength+1, "", "query is longer than %d characters", maxLength)
+	}
+
+	tokens, err := scan(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, t.errorf("unexpected %s", t.describe())
+	}
+
+	return expr, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// parseOr parses `and { OR and }`.
+func (p *parser) parseOr(depth int) (Expr, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("OR") {
+		p.next()
+
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses `unary { [AND] unary }`, the terms end at OR, ")" or the end of the query.
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind == tokenEOF || t.kind == tokenRParen || t.isKeyword("OR") {
+			return left, nil
+		}
+
+		if t.isKeyword("AND") {
+			p.next()
+		}
+
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+// parseUnary parses `- unary | ( or ) | term`.
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	if depth > maxDepth {
+		t := p.peek()
+		return nil, t.errorf("query is nested deeper than %d levels", maxDepth)
+	}
+
+	t := p.next()
+	switch t.kind {
+	case tokenMinus:
+		expr, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr, Position: t.pos}, nil
+	case tokenLParen:
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, closing.errorf("expected \")\" to close the one at position %d, got %s", t.pos, closing.describe())
+		}
+		return expr, nil
+	case tokenString:
+		return &Text{Value: t.text, Position: t.pos}, nil
+	case tokenWord:
+		if t.isKeyword("AND") || t.isKeyword("OR") {
+			return nil, t.errorf("expected a term before %s", t.describe())
+		}
+		if p.peek().kind != tokenOp {
+			return &Text{Value: t.text, Position: t.pos}, nil
+		}
+		return p.parseCondition(t)
+	default:
+		return nil, t.errorf("unexpected %s", t.describe())
+	}
+}
+
+// parseCondition parses the operator and the value following the field.
+func (p *parser) parseCondition(field token) (Expr, error) {
+	op := p.next()
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, value.errorf("expected a value after %s, got %s", op.describe(), value.describe())
+	}
+
+	return &Condition{
+		Field:    strings.ToLower(field.text),
+		Op:       op.op(),
+		Value:    value.text,
+		Position: field.pos,
+		OpPos:    op.pos,
+		ValuePos: value.pos,
+	}, nil
+}
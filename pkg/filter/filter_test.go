@@ -0,0 +1,249 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  Expr
+	}{
+		{
+			name:  "empty",
+			query: "  ",
+		},
+		{
+			name:  "condition",
+			query: "status:done",
+			want:  &Condition{Field: "status", Op: Equal, Value: "done", Position: 1, OpPos: 7, ValuePos: 8},
+		},
+		{
+			name:  "field is case-insensitive",
+			query: "Priority>=high",
+			want:  &Condition{Field: "priority", Op: GreaterOrEqual, Value: "high", Position: 1, OpPos: 9, ValuePos: 11},
+		},
+		{
+			name:  "not equal",
+			query: "tag!=home",
+			want:  &Condition{Field: "tag", Op: NotEqual, Value: "home", Position: 1, OpPos: 4, ValuePos: 6},
+		},
+		{
+			name:  "dash inside the value",
+			query: "due<2024-06-01",
+			want:  &Condition{Field: "due", Op: Less, Value: "2024-06-01", Position: 1, OpPos: 4, ValuePos: 5},
+		},
+		{
+			name:  "implicit and",
+			query: "report draft",
+			want: &And{
+				Left:  &Text{Value: "report", Position: 1},
+				Right: &Text{Value: "draft", Position: 8},
+			},
+		},
+		{
+			name:  "and is left-associative",
+			query: "a AND b c",
+			want: &And{
+				Left: &And{
+					Left:  &Text{Value: "a", Position: 1},
+					Right: &Text{Value: "b", Position: 7},
+				},
+				Right: &Text{Value: "c", Position: 9},
+			},
+		},
+		{
+			name:  "and binds tighter than or",
+			query: "a OR b c",
+			want: &Or{
+				Left: &Text{Value: "a", Position: 1},
+				Right: &And{
+					Left:  &Text{Value: "b", Position: 6},
+					Right: &Text{Value: "c", Position: 8},
+				},
+			},
+		},
+		{
+			name:  "parentheses group terms",
+			query: "(a OR b) c",
+			want: &And{
+				Left: &Or{
+					Left:  &Text{Value: "a", Position: 2},
+					Right: &Text{Value: "b", Position: 7},
+				},
+				Right: &Text{Value: "c", Position: 10},
+			},
+		},
+		{
+			name:  "negation binds tighter than and",
+			query: "-a b",
+			want: &And{
+				Left:  &Not{Expr: &Text{Value: "a", Position: 2}, Position: 1},
+				Right: &Text{Value: "b", Position: 4},
+			},
+		},
+		{
+			name:  "negated group",
+			query: "-(tag:home OR tag:work)",
+			want: &Not{
+				Expr: &Or{
+					Left:  &Condition{Field: "tag", Op: Equal, Value: "home", Position: 3, OpPos: 6, ValuePos: 7},
+					Right: &Condition{Field: "tag", Op: Equal, Value: "work", Position: 15, OpPos: 18, ValuePos: 19},
+				},
+				Position: 1,
+			},
+		},
+		{
+			name:  "lower case keywords are words",
+			query: "a or b",
+			want: &And{
+				Left: &And{
+					Left:  &Text{Value: "a", Position: 1},
+					Right: &Text{Value: "or", Position: 3},
+				},
+				Right: &Text{Value: "b", Position: 6},
+			},
+		},
+		{
+			name:  "quoted phrase",
+			query: `"quarterly report"`,
+			want:  &Text{Value: "quarterly report", Position: 1},
+		},
+		{
+			name:  "quoted value",
+			query: `project:"Home repairs"`,
+			want:  &Condition{Field: "project", Op: Equal, Value: "Home repairs", Position: 1, OpPos: 8, ValuePos: 9},
+		},
+		{
+			name:  "escaped quote",
+			query: `"say \"hi\""`,
+			want:  &Text{Value: `say "hi"`, Position: 1},
+		},
+		{
+			name:  "operators in a phrase are text",
+			query: `"a:b OR (c)"`,
+			want:  &Text{Value: "a:b OR (c)", Position: 1},
+		},
+		{
+			name:  "positions count runes",
+			query: "привет tag:дом",
+			want: &And{
+				Left:  &Text{Value: "привет", Position: 1},
+				Right: &Condition{Field: "tag", Op: Equal, Value: "дом", Position: 8, OpPos: 11, ValuePos: 12},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.query)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantPos int
+		wantMsg string
+	}{
+		{
+			name:    "unterminated phrase",
+			query:   `tag:work "report`,
+			wantPos: 10,
+			wantMsg: "unterminated phrase at position 10",
+		},
+		{
+			name:    "lone exclamation mark",
+			query:   "tag!home",
+			wantPos: 4,
+			wantMsg: `unexpected "!" (did you mean "!="?) at position 4`,
+		},
+		{
+			name:    "value is missing",
+			query:   "status:",
+			wantPos: 8,
+			wantMsg: `expected a value after ":", got end of the query at position 8`,
+		},
+		{
+			name:    "value is an operator",
+			query:   "due<=<2024-06-01",
+			wantPos: 6,
+			wantMsg: `expected a value after "<=", got "<" at position 6`,
+		},
+		{
+			name:    "operator without field",
+			query:   "a (>b)",
+			wantPos: 4,
+			wantMsg: `unexpected ">" at position 4`,
+		},
+		{
+			name:    "unclosed parenthesis",
+			query:   "(a OR b",
+			wantPos: 8,
+			wantMsg: `expected ")" to close the one at position 1, got end of the query at position 8`,
+		},
+		{
+			name:    "unexpected closing parenthesis",
+			query:   "a) b",
+			wantPos: 2,
+			wantMsg: `unexpected ")" at position 2`,
+		},
+		{
+			name:    "empty group",
+			query:   "()",
+			wantPos: 2,
+			wantMsg: `unexpected ")" at position 2`,
+		},
+		{
+			name:    "leading or",
+			query:   "OR a",
+			wantPos: 1,
+			wantMsg: `expected a term before "OR" at position 1`,
+		},
+		{
+			name:    "trailing and",
+			query:   "a AND",
+			wantPos: 6,
+			wantMsg: "unexpected end of the query at position 6",
+		},
+		{
+			name:    "double or",
+			query:   "a OR OR b",
+			wantPos: 6,
+			wantMsg: `expected a term before "OR" at position 6`,
+		},
+		{
+			name:    "too long",
+			query:   strings.Repeat("a", maxLength+1),
+			wantPos: maxLength + 1,
+			wantMsg: "query is longer than 1024 characters at position 1025",
+		},
+		{
+			name:    "too deep",
+			query:   strings.Repeat("(", maxDepth+2) + "a" + strings.Repeat(")", maxDepth+2),
+			wantPos: maxDepth + 2,
+			wantMsg: "query is nested deeper than 32 levels at position 34",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+
+			var queryErr *Error
+			if assert.ErrorAs(t, err, &queryErr) {
+				assert.Equal(t, tt.wantPos, queryErr.Pos)
+				assert.Equal(t, tt.wantMsg, queryErr.Error())
+			}
+		})
+	}
+}
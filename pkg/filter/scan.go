@@ -0,0 +1,129 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenMinus
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && t.text == keyword
+}
+
+func (t token) op() Op {
+	if t.text == ":" {
+		return Equal
+	}
+	return Op(t.text)
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of the query"
+	case tokenString:
+		return fmt.Sprintf("phrase %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+func (t token) errorf(format string, args ...any) *Error {
+	text := t.text
+	if t.kind == tokenEOF {
+		text = t.describe()
+	}
+	return Errorf(t.pos, text, format, args...)
+}
+
+// isDelimiter reports whether the rune ends a word.
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()"`, r) || strings.ContainsRune(":=!<>", r)
+}
+
+// scan splits the query into tokens, positions count runes starting from 1.
+func scan(query string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(query)
+	)
+
+	for i := 0; i < len(runes); {
+		r, pos := runes[i], i+1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && (len(tokens) == 0 || precedesTerm(tokens[len(tokens)-1], runes[i-1])):
+			tokens = append(tokens, token{kind: tokenMinus, text: "-", pos: pos})
+			i++
+		case strings.ContainsRune(":=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != ':' && r != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, Errorf(pos, op, `unexpected "!" (did you mean "!="?)`)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: pos})
+			i += utf8.RuneCountInString(op)
+		case r == '"':
+			var text strings.Builder
+			for i++; ; i++ {
+				if i == len(runes) {
+					return nil, Errorf(pos, `"`, "unterminated phrase")
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				} else if runes[i] == '"' {
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), pos: pos})
+		default:
+			start := i
+			for i < len(runes) && !isDelimiter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: pos})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// precedesTerm reports whether "-" after the token (and the rune right before "-") starts a negated term
+// rather than continues a value like in `due>2024-06-01`.
+func precedesTerm(prev token, before rune) bool {
+	if prev.kind == tokenOp {
+		return false
+	}
+	return unicode.IsSpace(before) || prev.kind == tokenLParen
+}
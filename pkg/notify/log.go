@@ -0,0 +1,20 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/zenorachi/todo-service/pkg/logger"
+)
+
+// LogNotifier writes notifications to the service log, it is useful for development.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification Notification) error {
+	logger.Info("reminder", "user", notification.UserID, "task", notification.TaskID,
+		"title", notification.Title, "due_date", notification.DueDate)
+	return nil
+}
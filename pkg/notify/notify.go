@@ -0,0 +1,19 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+type Notification struct {
+	UserID  int        `json:"user_id"`
+	Email   string     `json:"email"`
+	TaskID  int        `json:"task_id"`
+	Title   string     `json:"title"`
+	DueDate *time.Time `json:"due_date,omitempty"`
+	FireAt  time.Time  `json:"fire_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPNotifier sends notifications by email to the user's address.
+type SMTPNotifier struct {
+	host    string
+	addr    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+func NewSMTPNotifier(host, port, from, username, password string, timeout time.Duration) *SMTPNotifier {
+	var auth smtp.Auth
+	if len(username) != 0 {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPNotifier{
+		host:    host,
+		addr:    net.JoinHostPort(host, port),
+		from:    from,
+		auth:    auth,
+		timeout: timeout,
+	}
+}
+
+// Notify sends the email the way smtp.SendMail does, but the whole session is bounded by the timeout
+// and the context, so a stuck server does not hang the caller.
+func (n *SMTPNotifier) Notify(ctx context.Context, notification Notification) error {
+	if len(notification.Email) == 0 {
+		return fmt.Errorf("user %d has no email", notification.UserID)
+	}
+
+	dialer := net.Dialer{Timeout: n.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if n.timeout != 0 && (!ok || time.Until(deadline) > n.timeout) {
+		deadline, ok = time.Now().Add(n.timeout), true
+	}
+	if ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+
+	// the connection is closed on cancellation, so the pending read or write returns at once
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		_ = conn.Close()
+		return contextError(ctx, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if err = n.send(client, notification); err != nil {
+		return contextError(ctx, err)
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) send(client *smtp.Client, notification Notification) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+
+	if n.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support authentication")
+		}
+		if err := client.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(notification.Email); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(n.message(notification)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) message(notification Notification) []byte {
+	var msg strings.Builder
+
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", notification.Email)
+	fmt.Fprintf(&msg, "Subject: Reminder: %s\r\n", sanitizeHeader(notification.Title))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	if notification.DueDate != nil {
+		fmt.Fprintf(&msg, "%s is due %s.\r\n", notification.Title, notification.DueDate.Format(time.RFC1123Z))
+	} else {
+		fmt.Fprintf(&msg, "Reminder about %s.\r\n", notification.Title)
+	}
+
+	return []byte(msg.String())
+}
+
+// contextError reports the cancellation of the context instead of the error of the closed connection.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
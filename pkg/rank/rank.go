@@ -0,0 +1,107 @@
+package rank
+
+import "strings"
+
+// Ranks are base62 strings compared byte-wise (the column should use the "C" collation),
+// a rank between any two others can always be made, so reordering touches only the moved row.
+// The generated ranks never end with the lowest digit, otherwise nothing would fit right before them.
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Initial is the rank of the first item, it is long enough for the items appended
+// to the end or put to the beginning one by one to keep the same length.
+const Initial = "V000000000V"
+
+// MaxLength is the length a rank may grow to by the repeated inserts at the same place, a longer one
+// means the items should be renumbered (see Sequence). It leaves a margin to the rank column size.
+const MaxLength = 128
+
+// Sequence returns n ascending ranks starting from Initial, the ranks the items get when they are
+// appended one by one. It is used to renumber the items keeping their order.
+func Sequence(n int) []string {
+	ranks := make([]string, 0, n)
+	for i, r := 0, Initial; i < n; i, r = i+1, increment(r) {
+		ranks = append(ranks, r)
+	}
+
+	return ranks
+}
+
+// Between returns a rank greater than prev and less than next, an empty prev or next means no bound.
+// If next is not greater than prev, next is ignored.
+func Between(prev, next string) string {
+	if next != "" && next <= prev {
+		next = ""
+	}
+
+	switch {
+	case prev == "" && next == "":
+		return Initial
+	case next == "":
+		return increment(prev)
+	case prev == "":
+		if rank, ok := decrement(next); ok {
+			return rank
+		}
+	}
+
+	var result []byte
+	for i := 0; ; i++ {
+		low := 0
+		if i < len(prev) {
+			low = strings.IndexByte(digits, prev[i])
+		}
+
+		high := len(digits)
+		if next != "" && i < len(next) {
+			high = strings.IndexByte(digits, next[i])
+		}
+
+		switch {
+		case high-low > 1:
+			return string(append(result, digits[(low+high)/2]))
+		case high-low == 1:
+			// the result is already less than next, only prev bounds the rest
+			result = append(result, digits[low])
+			next = ""
+		default:
+			result = append(result, digits[low])
+		}
+	}
+}
+
+// increment adds one to the last digit of the rank keeping its length, it is extended
+// only when the rank consists of the highest digits.
+func increment(rank string) string {
+	result := []byte(rank)
+	for i := len(result) - 1; i >= 0; i-- {
+		if d := strings.IndexByte(digits, result[i]); d < len(digits)-1 {
+			result[i] = digits[d+1]
+			return string(result)
+		}
+
+		result[i] = digits[0]
+		if i == len(result)-1 {
+			result[i] = digits[1]
+		}
+	}
+
+	return rank + digits[len(digits)/2:len(digits)/2+1]
+}
+
+// decrement subtracts one from the last digit of the rank keeping its length,
+// it fails when the rank consists of the lowest digits.
+func decrement(rank string) (string, bool) {
+	result := []byte(rank)
+	for i := len(result) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, result[i])
+		if i == len(result)-1 && d > 1 || i < len(result)-1 && d > 0 {
+			result[i] = digits[d-1]
+			return string(result), true
+		}
+
+		result[i] = digits[len(digits)-1]
+	}
+
+	return "", false
+}
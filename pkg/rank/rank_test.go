@@ -0,0 +1,110 @@
+package rank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+		want string
+	}{
+		{name: "no bounds", prev: "", next: "", want: Initial},
+		{name: "after the last", prev: "V000000001V", next: "", want: "V000000001W"},
+		{name: "after the highest digits", prev: "zzzz", next: "", want: "zzzzV"},
+		{name: "before the first", prev: "", next: "V000000001V", want: "V000000001U"},
+		{name: "before the lowest digits", prev: "", next: "0001", want: "0000V"},
+		{name: "before the lowest rank", prev: "", next: "1", want: "0V"},
+		{name: "in the middle", prev: "V000000001V", next: "V000000003V", want: "V000000002"},
+		{name: "adjacent", prev: "V000000001V", next: "V000000001W", want: "V000000001VV"},
+		{name: "prefix", prev: "V", next: "V1", want: "V0V"},
+		{name: "next is not greater", prev: "b", next: "a", want: "c"},
+		{name: "equal bounds", prev: "a", next: "a", want: "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Between(tt.prev, tt.next)
+
+			assert.Equal(t, tt.want, got)
+			assert.Greater(t, got, tt.prev)
+			if tt.next > tt.prev {
+				assert.Less(t, got, tt.next)
+			}
+		})
+	}
+}
+
+func TestSequence(t *testing.T) {
+	ranks := Sequence(100)
+
+	assert.Len(t, ranks, 100)
+	assert.Equal(t, Initial, ranks[0])
+	for i := 1; i < len(ranks); i++ {
+		assert.Equal(t, Between(ranks[i-1], ""), ranks[i])
+		assert.Len(t, ranks[i], len(Initial))
+	}
+
+	assert.Empty(t, Sequence(0))
+}
+
+func TestBetween_MaxLength(t *testing.T) {
+	// the rank column is VARCHAR(255)
+	const columnLength = 255
+
+	tests := []struct {
+		name   string
+		toPrev bool
+	}{
+		{name: "inserting right after the same task", toPrev: true},
+		{name: "inserting right before the same task"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				// the moved item goes between the first two items of the list, the rest of them are the moved ones
+				items      = Sequence(3)
+				renumbered int
+			)
+
+			for i := 0; i < 5000; i++ {
+				prev, next := items[0], items[1]
+				if !tt.toPrev {
+					prev, next = items[len(items)-2], items[len(items)-1]
+				}
+
+				rank := Between(prev, next)
+				if !assert.True(t, prev < rank && rank < next, "%q is not between %q and %q", rank, prev, next) {
+					return
+				}
+
+				if len(rank) > MaxLength {
+					// the way the repository handles it: the items are renumbered and the move is repeated
+					items = Sequence(len(items))
+					renumbered++
+					i--
+					continue
+				}
+
+				if tt.toPrev {
+					items = append([]string{items[0], rank}, items[1:]...)
+				} else {
+					items = append(items[:len(items)-1:len(items)-1], rank, items[len(items)-1])
+				}
+			}
+
+			assert.Positive(t, renumbered)
+			for i, rank := range items {
+				assert.LessOrEqual(t, len(rank), columnLength)
+				if i > 0 {
+					assert.Less(t, items[i-1], rank)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported subset of RFC 5545 recurrence rules: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY),
+// INTERVAL, COUNT, UNTIL, BYDAY (with ordinals for MONTHLY and YEARLY), BYMONTHDAY, BYMONTH and WKST.
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// UNTIL forms: UTC time, floating (local) time and date.
+const (
+	untilFormat         = "20060102T150405Z"
+	untilFloatingFormat = "20060102T150405"
+	untilDateFormat     = "20060102"
+)
+
+// searchLimit bounds the search for the next occurrence, so a rule that never matches
+// (e.g. BYMONTHDAY=31;BYMONTH=2) does not loop forever.
+const searchLimit = 100 * 366
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Weekday is a BYDAY value, N is the ordinal of the weekday within the month (or the year),
+// negative values count from the end, zero means every such weekday.
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+
+	// untilLayout is the form UNTIL is given in, the floating and the date ones are read in the location
+	// of the occurrences, so Until keeps their wall clock in UTC.
+	untilLayout string
+}
+
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if len(s) == 0 {
+		return Rule{}, ErrInvalidRule
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || len(value) == 0 {
+			return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(value)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly && rule.Freq != Yearly {
+				err = fmt.Errorf("unsupported frequency %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(value)
+		case "COUNT":
+			rule.Count, err = parsePositive(value)
+		case "UNTIL":
+			rule.Until, rule.untilLayout, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseList(value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseList(value, 1, 12)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(value)]
+			if !ok {
+				err = fmt.Errorf("invalid week start %q", value)
+			}
+			rule.WeekStart = day
+		default:
+			err = fmt.Errorf("unsupported part %q", key)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	if len(rule.Freq) == 0 {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	if rule.Count != 0 && !rule.Until.IsZero() {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL must not occur together", ErrInvalidRule)
+	}
+
+	// RFC 5545 forbids BYMONTHDAY with WEEKLY
+	if len(rule.ByMonthDay) != 0 && rule.Freq == Weekly {
+		return Rule{}, fmt.Errorf("%w: BYMONTHDAY is not allowed with WEEKLY", ErrInvalidRule)
+	}
+
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return Rule{}, fmt.Errorf("%w: BYDAY ordinals are allowed only with MONTHLY or YEARLY", ErrInvalidRule)
+		}
+	}
+
+	return rule, nil
+}
+
+// Next returns the first occurrence strictly after current, treating current as an occurrence
+// of the rule (it anchors the interval and the defaults taken from the start date).
+// The returned rule is the remainder of the series: its COUNT is decreased by the current occurrence.
+// ok is false when the series is over.
+func (r Rule) Next(current time.Time) (next time.Time, rest Rule, ok bool) {
+	if r.Count == 1 {
+		return time.Time{}, Rule{}, false
+	}
+
+	until := r.until(current.Location())
+
+	candidate := current
+	for i := 0; i < searchLimit; i++ {
+		candidate = candidate.AddDate(0, 0, 1)
+		if !until.IsZero() && candidate.After(until) {
+			return time.Time{}, Rule{}, false
+		}
+
+		if r.matches(candidate, current) {
+			rest = r
+			if rest.Count != 0 {
+				rest.Count--
+			}
+			return candidate, rest, true
+		}
+	}
+
+	return time.Time{}, Rule{}, false
+}
+
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+
+	if r.Count != 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.untilString())
+	}
+
+	if len(r.ByDay) != 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			if day.N != 0 {
+				days = append(days, strconv.Itoa(day.N)+weekdayNames[day.Day])
+			} else {
+				days = append(days, weekdayNames[day.Day])
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) != 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonth) != 0 {
+		months := make([]string, 0, len(r.ByMonth))
+		for _, month := range r.ByMonth {
+			months = append(months, strconv.Itoa(int(month)))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// until returns the last moment of the series in the location of the occurrences,
+// a date UNTIL includes the whole day.
+func (r Rule) until(location *time.Location) time.Time {
+	if r.Until.IsZero() {
+		return time.Time{}
+	}
+
+	year, month, day := r.Until.Date()
+	switch r.untilLayout {
+	case untilDateFormat:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, location).Add(-time.Nanosecond)
+	case untilFloatingFormat:
+		hour, minute, second := r.Until.Clock()
+		return time.Date(year, month, day, hour, minute, second, 0, location)
+	}
+
+	return r.Until
+}
+
+func (r Rule) untilString() string {
+	if r.untilLayout == untilDateFormat || r.untilLayout == untilFloatingFormat {
+		return r.Until.Format(r.untilLayout)
+	}
+
+	return r.Until.UTC().Format(untilFormat)
+}
+
+func (r Rule) matches(day, start time.Time) bool {
+	if len(r.ByMonth) != 0 && !containsMonth(r.ByMonth, day.Month()) {
+		return false
+	}
+
+	switch r.Freq {
+	case Daily:
+		if daysBetween(start, day)%r.Interval != 0 {
+			return false
+		}
+		return r.matchesByMonthDay(day) && r.matchesByDay(day, false)
+	case Weekly:
+		weeks := daysBetween(r.weekStart(start), r.weekStart(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return r.matchesByDay(day, false)
+	case Monthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		return r.matchesDayOfPeriod(day, start, false)
+	case Yearly:
+		if (day.Year()-start.Year())%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 && day.Month() != start.Month() {
+			return false
+		}
+		return r.matchesDayOfPeriod(day, start, len(r.ByMonth) == 0)
+	}
+
+	return false
+}
+
+// matchesDayOfPeriod checks the day rules of MONTHLY and YEARLY frequencies,
+// the day of the start date is used when neither BYMONTHDAY nor BYDAY is set.
+func (r Rule) matchesDayOfPeriod(day, start time.Time, ordinalInYear bool) bool {
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		return day.Day() == start.Day()
+	}
+
+	return r.matchesByMonthDay(day) && r.matchesByDay(day, ordinalInYear)
+}
+
+func (r Rule) matchesByMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	last := daysIn(day.Year(), day.Month())
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay > 0 && day.Day() == monthDay || monthDay < 0 && day.Day() == last+monthDay+1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) matchesByDay(day time.Time, ordinalInYear bool) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	var position, total int
+	if ordinalInYear {
+		position = (day.YearDay()-1)/7 + 1
+		total = (daysInYear(day.Year())-day.YearDay())/7 + position
+	} else {
+		position = (day.Day()-1)/7 + 1
+		total = (daysIn(day.Year(), day.Month())-day.Day())/7 + position
+	}
+
+	for _, weekday := range r.ByDay {
+		if weekday.Day != day.Weekday() {
+			continue
+		}
+		if weekday.N == 0 || weekday.N == position || weekday.N == position-total-1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// daysBetween returns the number of calendar days between the dates, ignoring clock time and DST shifts.
+func daysBetween(from, to time.Time) int {
+	lhs := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	rhs := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(rhs.Sub(lhs).Hours() / 24)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, string, error) {
+	for _, layout := range []string{untilFormat, untilFloatingFormat, untilDateFormat} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return until, layout, nil
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("invalid until %q", value)
+}
+
+func parseList(value string, min, max int) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("invalid value %q", item)
+		}
+		list = append(list, n)
+	}
+	sort.Ints(list)
+	return list, nil
+}
+
+func parseByDay(value string) ([]Weekday, error) {
+	var days []Weekday
+	for _, item := range strings.Split(strings.ToUpper(value), ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		var n int
+		if ordinal := item[:len(item)-2]; len(ordinal) != 0 {
+			var err error
+			n, err = strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", item)
+			}
+		}
+
+		days = append(days, Weekday{N: n, Day: day})
+	}
+	return days, nil
+}
@@ -0,0 +1,258 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{name: "OK daily", rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "OK prefix and lower case", rule: "RRULE:freq=weekly;byday=mo,we", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "OK interval and count", rule: "FREQ=MONTHLY;INTERVAL=2;COUNT=5", want: "FREQ=MONTHLY;INTERVAL=2;COUNT=5"},
+		{name: "OK until", rule: "FREQ=DAILY;UNTIL=20261231T235959Z", want: "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{name: "OK until date", rule: "FREQ=DAILY;UNTIL=20261231", want: "FREQ=DAILY;UNTIL=20261231"},
+		{name: "OK floating until", rule: "FREQ=DAILY;UNTIL=20261231T090000", want: "FREQ=DAILY;UNTIL=20261231T090000"},
+		{name: "OK ordinal weekdays", rule: "FREQ=MONTHLY;BYDAY=-1FR,2MO", want: "FREQ=MONTHLY;BYDAY=-1FR,2MO"},
+		{name: "OK sorted month days", rule: "FREQ=MONTHLY;BYMONTHDAY=15,-1,1", want: "FREQ=MONTHLY;BYMONTHDAY=-1,1,15"},
+		{name: "OK week start", rule: "FREQ=WEEKLY;WKST=SU", want: "FREQ=WEEKLY;WKST=SU"},
+		{name: "ERROR empty", rule: "", wantErr: true},
+		{name: "ERROR no freq", rule: "INTERVAL=2", wantErr: true},
+		{name: "ERROR unsupported freq", rule: "FREQ=HOURLY", wantErr: true},
+		{name: "ERROR zero interval", rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "ERROR count with until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20261231", wantErr: true},
+		{name: "ERROR ordinal with weekly", rule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "ERROR month day with weekly", rule: "FREQ=WEEKLY;BYDAY=MO;BYMONTHDAY=1", wantErr: true},
+		{name: "ERROR invalid weekday", rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "ERROR month day out of range", rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{name: "ERROR unsupported part", rule: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "ERROR part without value", rule: "FREQ=DAILY;COUNT=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRule)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, rule.String())
+			}
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("error loading location: %v\n", err)
+	}
+
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		current  time.Time
+		want     time.Time
+		wantRest string
+		wantOk   bool
+	}{
+		{
+			name:     "daily",
+			rule:     "FREQ=DAILY",
+			current:  date(2026, time.October, 18, 9),
+			want:     date(2026, time.October, 19, 9),
+			wantRest: "FREQ=DAILY",
+			wantOk:   true,
+		},
+		{
+			name:     "daily with interval",
+			rule:     "FREQ=DAILY;INTERVAL=3",
+			current:  date(2026, time.October, 30, 9),
+			want:     date(2026, time.November, 2, 9),
+			wantRest: "FREQ=DAILY;INTERVAL=3",
+			wantOk:   true,
+		},
+		{
+			name:     "weekly on the weekday of the start",
+			rule:     "FREQ=WEEKLY",
+			current:  date(2026, time.October, 18, 9), // Sunday
+			want:     date(2026, time.October, 25, 9),
+			wantRest: "FREQ=WEEKLY",
+			wantOk:   true,
+		},
+		{
+			name:     "weekly by days",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,FR",
+			current:  date(2026, time.October, 19, 9), // Monday
+			want:     date(2026, time.October, 23, 9),
+			wantRest: "FREQ=WEEKLY;BYDAY=MO,FR",
+			wantOk:   true,
+		},
+		{
+			name:     "every other week by days",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			current:  date(2026, time.October, 23, 9), // Friday
+			want:     date(2026, time.November, 2, 9),
+			wantRest: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			wantOk:   true,
+		},
+		{
+			name:     "monthly skips months without the day",
+			rule:     "FREQ=MONTHLY",
+			current:  date(2026, time.January, 31, 9),
+			want:     date(2026, time.March, 31, 9),
+			wantRest: "FREQ=MONTHLY",
+			wantOk:   true,
+		},
+		{
+			name:     "monthly on the last day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			current:  date(2026, time.January, 31, 9),
+			want:     date(2026, time.February, 28, 9),
+			wantRest: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			wantOk:   true,
+		},
+		{
+			name:     "monthly on the last day of a leap year february",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			current:  date(2028, time.January, 31, 9),
+			want:     date(2028, time.February, 29, 9),
+			wantRest: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			wantOk:   true,
+		},
+		{
+			name:     "monthly on the last friday",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR",
+			current:  date(2026, time.October, 30, 9),
+			want:     date(2026, time.November, 27, 9),
+			wantRest: "FREQ=MONTHLY;BYDAY=-1FR",
+			wantOk:   true,
+		},
+		{
+			name:     "monthly on the second monday",
+			rule:     "FREQ=MONTHLY;BYDAY=2MO",
+			current:  date(2026, time.October, 12, 9),
+			want:     date(2026, time.November, 9, 9),
+			wantRest: "FREQ=MONTHLY;BYDAY=2MO",
+			wantOk:   true,
+		},
+		{
+			name:     "yearly on february 29",
+			rule:     "FREQ=YEARLY",
+			current:  date(2028, time.February, 29, 9),
+			want:     date(2032, time.February, 29, 9),
+			wantRest: "FREQ=YEARLY",
+			wantOk:   true,
+		},
+		{
+			name:     "count decreases",
+			rule:     "FREQ=DAILY;COUNT=3",
+			current:  date(2026, time.October, 18, 9),
+			want:     date(2026, time.October, 19, 9),
+			wantRest: "FREQ=DAILY;COUNT=2",
+			wantOk:   true,
+		},
+		{
+			name:    "count is over",
+			rule:    "FREQ=DAILY;COUNT=1",
+			current: date(2026, time.October, 18, 9),
+		},
+		{
+			name:     "until includes the last occurrence",
+			rule:     "FREQ=DAILY;UNTIL=20261019T090000Z",
+			current:  date(2026, time.October, 18, 9),
+			want:     date(2026, time.October, 19, 9),
+			wantRest: "FREQ=DAILY;UNTIL=20261019T090000Z",
+			wantOk:   true,
+		},
+		{
+			name:    "until is over",
+			rule:    "FREQ=DAILY;UNTIL=20261019T085959Z",
+			current: date(2026, time.October, 18, 9),
+		},
+		{
+			name:     "until date includes the whole day",
+			rule:     "FREQ=DAILY;UNTIL=20261019",
+			current:  date(2026, time.October, 18, 23),
+			want:     date(2026, time.October, 19, 23),
+			wantRest: "FREQ=DAILY;UNTIL=20261019",
+			wantOk:   true,
+		},
+		{
+			name:    "until date is over",
+			rule:    "FREQ=DAILY;UNTIL=20261019",
+			current: date(2026, time.October, 19, 9),
+		},
+		{
+			name:     "until date is the day in the location of the task",
+			rule:     "FREQ=DAILY;UNTIL=20261019",
+			current:  time.Date(2026, time.October, 18, 23, 30, 0, 0, berlin),
+			want:     time.Date(2026, time.October, 19, 23, 30, 0, 0, berlin),
+			wantRest: "FREQ=DAILY;UNTIL=20261019",
+			wantOk:   true,
+		},
+		{
+			name:     "floating until is read in the location of the task",
+			rule:     "FREQ=DAILY;UNTIL=20261019T090000",
+			current:  time.Date(2026, time.October, 18, 9, 0, 0, 0, berlin),
+			want:     time.Date(2026, time.October, 19, 9, 0, 0, 0, berlin),
+			wantRest: "FREQ=DAILY;UNTIL=20261019T090000",
+			wantOk:   true,
+		},
+		{
+			name:    "floating until is over in the location of the task",
+			rule:    "FREQ=DAILY;UNTIL=20261019T085959",
+			current: time.Date(2026, time.October, 18, 9, 0, 0, 0, berlin),
+		},
+		{
+			name:    "never matches",
+			rule:    "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=31",
+			current: date(2026, time.October, 18, 9),
+		},
+		{
+			name:     "daily keeps the wall time over the DST start",
+			rule:     "FREQ=DAILY",
+			current:  time.Date(2026, time.March, 28, 9, 0, 0, 0, berlin),
+			want:     time.Date(2026, time.March, 29, 9, 0, 0, 0, berlin),
+			wantRest: "FREQ=DAILY",
+			wantOk:   true,
+		},
+		{
+			name:     "weekly keeps the wall time over the DST end",
+			rule:     "FREQ=WEEKLY",
+			current:  time.Date(2026, time.October, 20, 9, 0, 0, 0, berlin),
+			want:     time.Date(2026, time.October, 27, 9, 0, 0, 0, berlin),
+			wantRest: "FREQ=WEEKLY",
+			wantOk:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("error parsing rule: %v\n", err)
+			}
+
+			next, rest, ok := rule.Next(tt.current)
+
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.True(t, tt.want.Equal(next), "want %v, got %v", tt.want, next)
+				assert.Equal(t, tt.wantRest, rest.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	var (
+		store   = NewLocalStore(t.TempDir())
+		ctx     = context.Background()
+		key     = "1/2/report"
+		content = "quarterly report"
+	)
+
+	err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain")
+	assert.NoError(t, err)
+
+	got, err := store.Get(ctx, key)
+	if assert.NoError(t, err) {
+		body, err := io.ReadAll(got)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(body))
+		assert.NoError(t, got.Close())
+	}
+
+	assert.NoError(t, store.Delete(ctx, key))
+	assert.NoError(t, store.Delete(ctx, key))
+
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStore_PathTraversal(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		root    = filepath.Join(dir, "blobs")
+		store   = NewLocalStore(root)
+		ctx     = context.Background()
+		content = "payload"
+	)
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "parent directory", key: "../escaped", want: filepath.Join(root, "escaped")},
+		{name: "nested parent directories", key: "1/../../../escaped", want: filepath.Join(root, "escaped")},
+		{name: "absolute path", key: "/escaped", want: filepath.Join(root, "escaped")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Put(ctx, tt.key, strings.NewReader(content), int64(len(content)), "text/plain")
+			assert.NoError(t, err)
+
+			body, err := os.ReadFile(tt.want)
+			assert.NoError(t, err)
+			assert.Equal(t, content, string(body))
+
+			_, err = os.Stat(filepath.Join(dir, "escaped"))
+			assert.ErrorIs(t, err, os.ErrNotExist)
+
+			assert.NoError(t, store.Delete(ctx, tt.key))
+		})
+	}
+}
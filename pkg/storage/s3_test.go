@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the examples of the AWS Signature Version 4 documentation and test suite
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+func TestSigningKey(t *testing.T) {
+	key := signingKey(testSecretKey, time.Date(2012, 2, 15, 0, 0, 0, 0, time.UTC), "us-east-1", "iam")
+
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestSignatureV4(t *testing.T) {
+	// get-vanilla of the AWS test suite
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatalf("error creating request: %v\n", err)
+	}
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+
+	got := signatureV4(req, now, "us-east-1", "service", testSecretKey, []string{"host", "x-amz-date"},
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+
+	assert.Equal(t, "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", got)
+}
+
+func TestS3Store(t *testing.T) {
+	server := httptest.NewServer(newTestS3(t))
+	defer server.Close()
+
+	store, err := NewS3Store(server.URL, "attachments", "us-east-1", testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatalf("error creating store: %v\n", err)
+	}
+
+	var (
+		ctx     = context.Background()
+		key     = "1/2/report"
+		content = "quarterly report"
+	)
+
+	err = store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain")
+	assert.NoError(t, err)
+
+	got, err := store.Get(ctx, key)
+	if assert.NoError(t, err) {
+		body, err := io.ReadAll(got)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(body))
+		assert.NoError(t, got.Close())
+	}
+
+	assert.NoError(t, store.Delete(ctx, key))
+
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	wrong, err := NewS3Store(server.URL, "attachments", "us-east-1", testAccessKey, "wrong")
+	if err != nil {
+		t.Fatalf("error creating store: %v\n", err)
+	}
+	err = wrong.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain")
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestNewS3Store_InvalidEndpoint(t *testing.T) {
+	_, err := NewS3Store("localhost:9000", "attachments", "us-east-1", testAccessKey, testSecretKey)
+
+	assert.Error(t, err)
+}
+
+// newTestS3 is an in-memory S3 checking the signatures of the requests.
+func newTestS3(t *testing.T) http.Handler {
+	var (
+		mu      sync.Mutex
+		objects = make(map[string]string)
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now, err := time.Parse(s3TimeFormat, r.Header.Get("X-Amz-Date"))
+		if err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		signed := r.Clone(r.Context())
+		signed.URL.Host = r.Host
+		signature := signatureV4(signed, now, "us-east-1", s3ServiceName, testSecretKey,
+			strings.Split(s3SignedHeaders, ";"), r.Header.Get("X-Amz-Content-Sha256"))
+		if !strings.HasSuffix(r.Header.Get("Authorization"), "Signature="+signature) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, "SignatureDoesNotMatch")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("error reading body: %v\n", err)
+			}
+			objects[r.URL.Path] = string(body)
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
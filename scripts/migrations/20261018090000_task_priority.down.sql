@@ -0,0 +1,3 @@
+ALTER TABLE agenda DROP COLUMN IF EXISTS priority;
+
+DROP TYPE IF EXISTS priority_type;
@@ -0,0 +1,8 @@
+-- TASK PRIORITY --
+CREATE TYPE
+priority_type AS ENUM (
+    'none', 'low', 'medium', 'high', 'urgent'
+);
+
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS priority priority_type NOT NULL DEFAULT 'none';
@@ -0,0 +1,19 @@
+-- TAGS --
+CREATE TABLE IF NOT EXISTS
+tags (
+    id              SERIAL PRIMARY KEY,
+    user_id         INT NOT NULL,
+    name            VARCHAR(64) NOT NULL,
+    UNIQUE (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+-- TASK'S TAGS --
+CREATE TABLE IF NOT EXISTS
+agenda_tags (
+    task_id         INT NOT NULL,
+    tag_id          INT NOT NULL,
+    PRIMARY KEY (task_id, tag_id),
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
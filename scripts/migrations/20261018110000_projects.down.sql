@@ -0,0 +1,3 @@
+ALTER TABLE agenda DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,13 @@
+-- PROJECTS --
+CREATE TABLE IF NOT EXISTS
+projects (
+    id              SERIAL PRIMARY KEY,
+    user_id         INT NOT NULL,
+    name            VARCHAR(255) NOT NULL,
+    description     VARCHAR DEFAULT NULL,
+    UNIQUE (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS project_id INT DEFAULT NULL REFERENCES projects (id) ON DELETE SET NULL;
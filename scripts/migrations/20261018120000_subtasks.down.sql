@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS agenda_parent_id_idx;
+
+ALTER TABLE agenda DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,4 @@
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS parent_id INT DEFAULT NULL REFERENCES agenda (id);
+
+CREATE INDEX IF NOT EXISTS agenda_parent_id_idx ON agenda (parent_id);
@@ -0,0 +1 @@
+ALTER TABLE agenda DROP COLUMN IF EXISTS recurrence;
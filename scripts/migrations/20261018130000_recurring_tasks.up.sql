@@ -0,0 +1,2 @@
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS recurrence VARCHAR(255) NOT NULL DEFAULT '';
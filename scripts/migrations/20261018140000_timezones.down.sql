@@ -0,0 +1,4 @@
+ALTER TABLE agenda
+    ALTER COLUMN date TYPE TIMESTAMP USING date AT TIME ZONE 'UTC';
+
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- dates were stored without zone in server time, which is UTC in our deployments --
+ALTER TABLE agenda
+    ALTER COLUMN date TYPE TIMESTAMPTZ USING date AT TIME ZONE 'UTC';
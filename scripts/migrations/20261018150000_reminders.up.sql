@@ -0,0 +1,16 @@
+-- REMINDERS --
+CREATE TABLE IF NOT EXISTS
+reminders (
+    id              SERIAL PRIMARY KEY,
+    user_id         INT NOT NULL,
+    task_id         INT NOT NULL,
+    remind_at       TIMESTAMPTZ DEFAULT NULL,
+    offset_minutes  INT DEFAULT NULL,
+    sent_at         TIMESTAMPTZ DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS reminders_task_id_idx ON reminders (task_id);
+CREATE INDEX IF NOT EXISTS reminders_pending_idx ON reminders (id) WHERE sent_at IS NULL;
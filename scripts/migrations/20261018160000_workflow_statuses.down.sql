@@ -0,0 +1,15 @@
+CREATE TYPE
+task_type AS ENUM (
+    'done', 'not done'
+);
+
+UPDATE agenda SET status = CASE WHEN completed_at IS NULL THEN 'not done' ELSE 'done' END;
+
+ALTER TABLE agenda ALTER COLUMN status DROP NOT NULL;
+ALTER TABLE agenda ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE agenda ALTER COLUMN status TYPE task_type USING status::task_type;
+ALTER TABLE agenda ALTER COLUMN status SET DEFAULT 'not done';
+
+ALTER TABLE agenda DROP COLUMN IF EXISTS completed_at;
+
+DROP TABLE IF EXISTS statuses;
@@ -0,0 +1,30 @@
+-- WORKFLOW STATUSES --
+CREATE TABLE IF NOT EXISTS
+statuses (
+    id              SERIAL PRIMARY KEY,
+    user_id         INT NOT NULL,
+    project_id      INT DEFAULT NULL,
+    name            VARCHAR(64) NOT NULL,
+    completed       BOOLEAN NOT NULL DEFAULT FALSE,
+    position        INT NOT NULL DEFAULT 0,
+    transitions     VARCHAR(64)[] DEFAULT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS statuses_scope_name_idx ON statuses (user_id, COALESCE(project_id, 0), name);
+
+-- task status is a name from the user's workflow, "completed" is kept separately --
+UPDATE agenda SET status = 'not done' WHERE status IS NULL;
+
+ALTER TABLE agenda ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE agenda ALTER COLUMN status TYPE VARCHAR(64) USING status::TEXT;
+ALTER TABLE agenda ALTER COLUMN status SET DEFAULT 'not done';
+ALTER TABLE agenda ALTER COLUMN status SET NOT NULL;
+
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ DEFAULT NULL;
+
+UPDATE agenda SET completed_at = NOW() WHERE status = 'done';
+
+DROP TYPE IF EXISTS task_type;
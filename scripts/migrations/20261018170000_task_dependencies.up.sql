@@ -0,0 +1,12 @@
+-- TASK DEPENDENCIES --
+CREATE TABLE IF NOT EXISTS
+task_dependencies (
+    task_id         INT NOT NULL,
+    blocker_id      INT NOT NULL,
+    PRIMARY KEY (task_id, blocker_id),
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    FOREIGN KEY (blocker_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_dependencies_blocker_id_idx ON task_dependencies (blocker_id);
@@ -0,0 +1,6 @@
+DELETE FROM agenda WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS agenda_deleted_at_idx;
+
+ALTER TABLE agenda
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+-- TRASH --
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS agenda_deleted_at_idx ON agenda (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,14 @@
+-- TASK HISTORY --
+CREATE TABLE IF NOT EXISTS
+task_history (
+    id              BIGSERIAL PRIMARY KEY,
+    task_id         INT NOT NULL,
+    user_id         INT NOT NULL,
+    action          VARCHAR(32) NOT NULL,
+    changes         JSONB DEFAULT NULL,
+    changed_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS task_history_task_id_idx ON task_history (task_id, changed_at);
@@ -0,0 +1,14 @@
+-- COMMENTS --
+CREATE TABLE IF NOT EXISTS
+comments (
+    id              SERIAL PRIMARY KEY,
+    task_id         INT NOT NULL,
+    user_id         INT NOT NULL,
+    body            TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ DEFAULT NULL,
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS comments_task_id_idx ON comments (task_id, created_at);
@@ -0,0 +1,18 @@
+-- ATTACHMENTS --
+-- task_id is set to NULL when the task is purged, so the blobs of such attachments can be collected
+CREATE TABLE IF NOT EXISTS
+attachments (
+    id              SERIAL PRIMARY KEY,
+    task_id         INT DEFAULT NULL,
+    user_id         INT NOT NULL,
+    filename        VARCHAR(255) NOT NULL,
+    content_type    VARCHAR(255) NOT NULL,
+    size            BIGINT NOT NULL,
+    storage_key     VARCHAR(255) NOT NULL UNIQUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE SET NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS attachments_task_id_idx ON attachments (task_id);
+CREATE INDEX IF NOT EXISTS attachments_user_id_idx ON attachments (user_id);
@@ -0,0 +1,12 @@
+-- CHECKLISTS --
+CREATE TABLE IF NOT EXISTS
+checklist_items (
+    id              SERIAL PRIMARY KEY,
+    task_id         INT NOT NULL,
+    text            VARCHAR(255) NOT NULL,
+    checked         BOOLEAN NOT NULL DEFAULT FALSE,
+    position        INT NOT NULL,
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS checklist_items_task_id_idx ON checklist_items (task_id, position);
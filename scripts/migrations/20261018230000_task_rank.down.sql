@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS agenda_rank_idx;
+
+ALTER TABLE agenda
+    DROP COLUMN IF EXISTS rank;
@@ -0,0 +1,14 @@
+-- MANUAL ORDERING --
+-- ranks are compared byte-wise, so the "C" collation is required
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS rank VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+
+-- existing tasks keep their previous order (by priority and date)
+UPDATE agenda SET rank = ranked.rank
+FROM (
+    SELECT id, 'V' || LPAD(ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY priority DESC, date, id)::TEXT, 9, '0') || 'V' AS rank
+    FROM agenda
+) AS ranked
+WHERE agenda.id = ranked.id;
+
+CREATE INDEX IF NOT EXISTS agenda_rank_idx ON agenda (user_id, rank, id);
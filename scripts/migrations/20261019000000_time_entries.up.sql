@@ -0,0 +1,19 @@
+-- TIME TRACKING --
+CREATE TABLE IF NOT EXISTS
+time_entries (
+    id              SERIAL PRIMARY KEY,
+    task_id         INT NOT NULL,
+    user_id         INT NOT NULL,
+    started_at      TIMESTAMPTZ NOT NULL,
+    stopped_at      TIMESTAMPTZ DEFAULT NULL,
+    note            VARCHAR(255) NOT NULL DEFAULT '',
+    FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    CHECK (stopped_at IS NULL OR stopped_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS time_entries_task_id_idx ON time_entries (task_id, started_at);
+CREATE INDEX IF NOT EXISTS time_entries_user_id_idx ON time_entries (user_id, started_at);
+
+-- at most one running timer per user
+CREATE UNIQUE INDEX IF NOT EXISTS time_entries_running_idx ON time_entries (user_id) WHERE stopped_at IS NULL;
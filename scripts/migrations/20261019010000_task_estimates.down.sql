@@ -0,0 +1,3 @@
+ALTER TABLE agenda
+    DROP COLUMN IF EXISTS estimate_minutes,
+    DROP COLUMN IF EXISTS estimate_points;
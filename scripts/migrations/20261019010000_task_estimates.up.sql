@@ -0,0 +1,5 @@
+-- ESTIMATES --
+-- zero means the task is not estimated
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS estimate_minutes INT NOT NULL DEFAULT 0 CHECK (estimate_minutes >= 0),
+    ADD COLUMN IF NOT EXISTS estimate_points INT NOT NULL DEFAULT 0 CHECK (estimate_points >= 0);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS agenda_archived_at_idx;
+
+ALTER TABLE agenda
+    DROP COLUMN IF EXISTS archived_at;
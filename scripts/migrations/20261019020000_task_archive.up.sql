@@ -0,0 +1,5 @@
+-- ARCHIVE --
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS agenda_archived_at_idx ON agenda (user_id, archived_at) WHERE archived_at IS NOT NULL;
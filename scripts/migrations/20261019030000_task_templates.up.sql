@@ -0,0 +1,14 @@
+-- TASK TEMPLATES --
+CREATE TABLE IF NOT EXISTS
+templates (
+    id                  SERIAL PRIMARY KEY,
+    user_id             INT NOT NULL,
+    name                VARCHAR(64) NOT NULL,
+    title               VARCHAR(255) NOT NULL,
+    description         VARCHAR NOT NULL DEFAULT '',
+    due_offset_minutes  INT NOT NULL DEFAULT 0 CHECK (due_offset_minutes >= 0),
+    tags                VARCHAR(64)[] NOT NULL DEFAULT '{}',
+    checklist           VARCHAR(255)[] NOT NULL DEFAULT '{}',
+    UNIQUE (user_id, name),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
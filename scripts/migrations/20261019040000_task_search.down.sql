@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS agenda_search_vector_idx;
+
+ALTER TABLE agenda
+    DROP COLUMN IF EXISTS search_vector;
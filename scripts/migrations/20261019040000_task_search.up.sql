@@ -0,0 +1,8 @@
+-- TASK SEARCH --
+ALTER TABLE agenda
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS agenda_search_vector_idx ON agenda USING GIN (search_vector);
@@ -0,0 +1,4 @@
+UPDATE agenda SET date = NOW() WHERE date IS NULL;
+
+ALTER TABLE agenda
+    ALTER COLUMN date SET NOT NULL;
@@ -0,0 +1,3 @@
+-- OPTIONAL TASK DATE --
+ALTER TABLE agenda
+    ALTER COLUMN date DROP NOT NULL;
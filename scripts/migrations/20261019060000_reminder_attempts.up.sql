@@ -0,0 +1,5 @@
+-- REMINDER ATTEMPTS --
+-- attempts counts the deliveries started, a failed one is retried not earlier than next_attempt_at --
+ALTER TABLE reminders
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ DEFAULT NULL;
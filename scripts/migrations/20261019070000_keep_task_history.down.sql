@@ -0,0 +1,4 @@
+DELETE FROM task_history WHERE task_id NOT IN (SELECT id FROM agenda);
+
+ALTER TABLE task_history
+    ADD CONSTRAINT task_history_task_id_fkey FOREIGN KEY (task_id) REFERENCES agenda (id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+-- KEEP TASK HISTORY --
+-- the history outlives the task, it is not deleted when the task is purged from the trash --
+ALTER TABLE task_history
+    DROP CONSTRAINT IF EXISTS task_history_task_id_fkey;